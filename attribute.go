@@ -12,6 +12,7 @@ type (
 		key       string
 		value     string
 		blocked   bool
+		denied    bool
 
 		safeNamespace string
 		safeKey       string
@@ -39,17 +40,30 @@ func (p AttributePolicy) Apply(tag *Tag) {
 }
 
 func (a *Attribute) IsBlocked() bool {
-	return a.blocked
+	return a.blocked || a.denied
 }
 
 func (a *Attribute) Block() {
 	a.blocked = true
 }
 
+// Allow will allow the attribute in the sanitized output.
+// Denied attributes cannot be allowed.
 func (a *Attribute) Allow() {
 	a.blocked = false
 }
 
+// Deny will remove the attribute from the sanitized output.
+// Unlike Block, this decision is final, subsequent policies cannot allow the attribute again.
+func (a *Attribute) Deny() {
+	a.denied = true
+}
+
+// IsDenied checks if the attribute was denied, and cannot be allowed anymore.
+func (a *Attribute) IsDenied() bool {
+	return a.denied
+}
+
 func (a *Attribute) UnsafeKey() string {
 	return a.key
 }
@@ -0,0 +1,88 @@
+package sanitize
+
+import (
+	"regexp"
+	"strings"
+)
+
+// filterClasses keeps only the class tokens accepted by keep, which can also rename them.
+// The class attribute is removed when no tokens are left.
+func filterClasses(tag *Tag, keep func(class string) (string, bool)) {
+	for _, attr := range tag.attributes {
+		if attr.Key() != "class" {
+			continue
+		}
+
+		classes := strings.Fields(attr.value)
+		kept := classes[:0]
+
+		for _, class := range classes {
+			if class, ok := keep(class); ok {
+				kept = append(kept, class)
+			}
+		}
+
+		if len(kept) == 0 {
+			tag.RemoveAttr("class")
+			return
+		}
+
+		attr.SetValue(strings.Join(kept, " "))
+	}
+}
+
+// AllowClasses allows the class attribute, keeping only the given class tokens.
+// Class attributes without any remaining tokens are removed.
+//
+// Example:
+//
+//	sanitize.AllowClasses("btn", "btn-primary", "text-muted")
+func AllowClasses(classes ...string) Policy {
+	set := make(map[string]struct{}, len(classes))
+
+	for _, class := range classes {
+		set[strings.TrimSpace(class)] = struct{}{}
+	}
+
+	return allowClassesFunc(func(class string) bool {
+		_, ok := set[class]
+		return ok
+	})
+}
+
+// AllowClassPattern allows the class attribute, keeping only the class tokens fully matching the pattern.
+// Class attributes without any remaining tokens are removed.
+//
+// Example:
+//
+//	sanitize.AllowClassPattern(regexp.MustCompile(`ds-[a-z0-9-]+`))
+func AllowClassPattern(pattern *regexp.Regexp) Policy {
+	// Anchoring the whole pattern matches alternatives like a|ab against the full token.
+	anchored := regexp.MustCompile(`^(?:` + pattern.String() + `)$`)
+
+	return allowClassesFunc(anchored.MatchString)
+}
+
+// PrefixClasses prefixes all class tokens, like "btn" into "user-btn", so user content cannot
+// use the application's own classes. It should be declared after any class allowlist.
+func PrefixClasses(prefix string) Policy {
+	return TagPolicy(func(tag *Tag) {
+		filterClasses(tag, func(class string) (string, bool) {
+			return prefix + class, true
+		})
+	})
+}
+
+func allowClassesFunc(allowed func(class string) bool) Policy {
+	return TagPolicy(func(tag *Tag) {
+		filterClasses(tag, func(class string) (string, bool) {
+			return class, allowed(class)
+		})
+
+		for _, attr := range tag.attributes {
+			if attr.Key() == "class" {
+				attr.Allow()
+			}
+		}
+	})
+}
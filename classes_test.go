@@ -0,0 +1,73 @@
+package sanitize_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/sonalys/sanitize"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_AllowClasses(t *testing.T) {
+	t.Run("should keep only allowed tokens", func(t *testing.T) {
+		tag := &sanitize.Tag{}
+		tag.UpsertAttr("", "class", " btn  evil btn-primary ")
+
+		sanitize.Blacklist().Apply(tag)
+		sanitize.AllowClasses("btn", "btn-primary").Apply(tag)
+
+		attr := tag.Attrs()[0]
+		require.False(t, attr.IsBlocked())
+		require.Equal(t, "btn btn-primary", attr.Value())
+	})
+
+	t.Run("should remove empty class attributes", func(t *testing.T) {
+		tag := &sanitize.Tag{}
+		tag.UpsertAttr("", "class", "evil")
+		tag.UpsertAttr("", "title", "title")
+
+		sanitize.AllowClasses("btn").Apply(tag)
+
+		require.Len(t, tag.Attrs(), 1)
+		require.False(t, tag.HasAttr("class"))
+	})
+
+	t.Run("should keep denied attributes denied", func(t *testing.T) {
+		tag := &sanitize.Tag{}
+		tag.UpsertAttr("", "class", "btn")
+
+		sanitize.DenyAttrs("class").Apply(tag)
+		sanitize.AllowClasses("btn").Apply(tag)
+
+		require.True(t, tag.Attrs()[0].IsBlocked())
+	})
+}
+
+func Test_AllowClassPattern(t *testing.T) {
+	t.Run("should keep tokens fully matching the pattern", func(t *testing.T) {
+		tag := &sanitize.Tag{}
+		tag.UpsertAttr("", "class", "ds-button ds-Button x-ds-button ds-button-large")
+
+		sanitize.AllowClassPattern(regexp.MustCompile(`ds-[a-z-]+`)).Apply(tag)
+
+		require.Equal(t, "ds-button ds-button-large", tag.Attrs()[0].Value())
+	})
+
+	t.Run("should match the full token with alternatives", func(t *testing.T) {
+		tag := &sanitize.Tag{}
+		tag.UpsertAttr("", "class", "a ab abc")
+
+		sanitize.AllowClassPattern(regexp.MustCompile(`a|ab`)).Apply(tag)
+
+		require.Equal(t, "a ab", tag.Attrs()[0].Value())
+	})
+}
+
+func Test_PrefixClasses(t *testing.T) {
+	tag := &sanitize.Tag{}
+	tag.UpsertAttr("", "class", "btn btn-primary")
+
+	sanitize.PrefixClasses("user-").Apply(tag)
+
+	require.Equal(t, "user-btn user-btn-primary", tag.Attrs()[0].Value())
+}
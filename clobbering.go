@@ -0,0 +1,142 @@
+package sanitize
+
+import (
+	"strings"
+
+	"golang.org/x/net/html/atom"
+)
+
+// idRefAttrs are the normalized keys of attributes containing space separated id references.
+var idRefAttrs = map[string]struct{}{
+	"for":                   {},
+	"form":                  {},
+	"list":                  {},
+	"headers":               {},
+	"aria-activedescendant": {},
+	"aria-controls":         {},
+	"aria-describedby":      {},
+	"aria-details":          {},
+	"aria-errormessage":     {},
+	"aria-flowto":           {},
+	"aria-labelledby":       {},
+	"aria-owns":             {},
+}
+
+// fragmentAttrs are the normalized keys of attributes referencing ids as URL fragments, like #section.
+var fragmentAttrs = map[string]struct{}{
+	"href":       {},
+	"xlink:href": {},
+	"usemap":     {},
+}
+
+// clobberableNames are the document, window and form properties that can be overridden
+// by elements with the same id or name.
+var clobberableNames = map[string]struct{}{
+	// Document.
+	"activeElement": {}, "all": {}, "anchors": {}, "body": {}, "cookie": {}, "createElement": {},
+	"createTextNode": {}, "currentScript": {}, "defaultView": {}, "documentElement": {}, "domain": {},
+	"embeds": {}, "forms": {}, "getElementById": {}, "getElementsByClassName": {}, "getElementsByName": {},
+	"getElementsByTagName": {}, "head": {}, "images": {}, "implementation": {}, "links": {}, "location": {},
+	"plugins": {}, "querySelector": {}, "querySelectorAll": {}, "readyState": {}, "referrer": {},
+	"scripts": {}, "title": {}, "URL": {}, "write": {}, "writeln": {},
+	// Window.
+	"alert": {}, "close": {}, "confirm": {}, "console": {}, "crypto": {}, "document": {}, "eval": {},
+	"fetch": {}, "frames": {}, "history": {}, "length": {}, "localStorage": {}, "name": {},
+	"navigator": {}, "open": {}, "opener": {}, "origin": {}, "parent": {}, "postMessage": {},
+	"prompt": {}, "self": {}, "sessionStorage": {}, "setInterval": {}, "setTimeout": {}, "top": {},
+	"window": {},
+	// Forms and nodes.
+	"acceptCharset": {}, "action": {}, "addEventListener": {}, "attributes": {}, "children": {},
+	"className": {}, "dispatchEvent": {}, "elements": {}, "encoding": {}, "enctype": {}, "firstChild": {},
+	"id": {}, "innerHTML": {}, "lastChild": {}, "method": {}, "nodeName": {}, "nodeType": {},
+	"nodeValue": {}, "noValidate": {}, "outerHTML": {}, "ownerDocument": {}, "parentNode": {},
+	"removeEventListener": {}, "reset": {}, "style": {}, "submit": {}, "tagName": {}, "target": {},
+	"textContent": {},
+	// Objects.
+	"__proto__": {}, "constructor": {}, "hasOwnProperty": {}, "prototype": {}, "toString": {}, "valueOf": {},
+}
+
+// NamespaceIDs prefixes the values of id and name attributes with the namespace, preventing
+// user content from clobbering global variables, or the application's own elements.
+//
+// References to the prefixed ids are rewritten as well:
+//   - Fragment URLs in href, xlink:href and usemap, like #section.
+//   - Space separated id references, like label for, headers and aria-labelledby.
+//
+// Name attributes of meta and param tags are kept, as they are not element names.
+// Applying the policy more than once prefixes the values again.
+//
+// Example:
+//
+//	sanitize.NamespaceIDs("user-content-")
+func NamespaceIDs(namespace string) Policy {
+	return TagPolicy(func(tag *Tag) {
+		for _, attr := range tag.attributes {
+			key := attr.Key()
+
+			switch {
+			case key == "id":
+				attr.SetValue(namespaceID(namespace, attr.value))
+			case key == "name":
+				if tag.atom != atom.Meta && tag.atom != atom.Param {
+					attr.SetValue(namespaceID(namespace, attr.value))
+				}
+			case isAttrIn(key, fragmentAttrs):
+				if fragment, ok := strings.CutPrefix(strings.TrimSpace(attr.value), "#"); ok && fragment != "" {
+					attr.SetValue("#" + namespace + fragment)
+				}
+			case isAttrIn(key, idRefAttrs):
+				ids := strings.Fields(attr.value)
+				for i := range ids {
+					ids[i] = namespace + ids[i]
+				}
+				attr.SetValue(strings.Join(ids, " "))
+			}
+		}
+	})
+}
+
+// BlockClobbering denies id and name attributes whose values can clobber document, window or form
+// properties, like cookie, location or submit.
+// It accepts names as additional values to be denied, like the ids used by the application.
+func BlockClobbering(names ...string) Policy {
+	set := make(map[string]struct{}, len(clobberableNames)+len(names))
+
+	for name := range clobberableNames {
+		set[name] = struct{}{}
+	}
+
+	for _, name := range names {
+		set[strings.TrimSpace(name)] = struct{}{}
+	}
+
+	return TagPolicy(func(tag *Tag) {
+		for _, attr := range tag.attributes {
+			switch attr.Key() {
+			case "id":
+			case "name":
+				if tag.atom == atom.Meta || tag.atom == atom.Param {
+					continue
+				}
+			default:
+				continue
+			}
+
+			if _, ok := set[strings.TrimSpace(attr.value)]; ok {
+				attr.Deny()
+			}
+		}
+	})
+}
+
+func namespaceID(namespace, value string) string {
+	if value == "" {
+		return value
+	}
+	return namespace + value
+}
+
+func isAttrIn(key string, set map[string]struct{}) bool {
+	_, ok := set[key]
+	return ok
+}
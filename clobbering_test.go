@@ -0,0 +1,57 @@
+package sanitize_test
+
+import (
+	"testing"
+
+	"github.com/sonalys/sanitize"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_NamespaceIDs(t *testing.T) {
+	t.Run("should prefix ids and references", func(t *testing.T) {
+		got := sanitizeWith(t,
+			`<meta name="viewport" content="width=device-width">`+
+				`<h1 id="intro">Intro</h1><a href="#intro">top</a><a href="#">empty</a><a href="/page#intro">page</a>`+
+				`<label for="email" id="label">Email</label><input id="email" name="email" aria-labelledby="label intro">`+
+				`<img usemap="#map"><map name="map"></map>`,
+			sanitize.NamespaceIDs("uc-"),
+		)
+		require.Equal(t, `<html><head><meta name="viewport" content="width=device-width"/></head><body>`+
+			`<h1 id="uc-intro">Intro</h1><a href="#uc-intro">top</a><a href="#">empty</a><a href="/page#intro">page</a>`+
+			`<label for="uc-email" id="uc-label">Email</label><input id="uc-email" name="uc-email" aria-labelledby="uc-label uc-intro"/>`+
+			`<img usemap="#uc-map"/><map name="uc-map"></map>`+
+			`</body></html>`, got)
+	})
+}
+
+func Test_BlockClobbering(t *testing.T) {
+	tests := []struct {
+		name   string
+		key    string
+		value  string
+		denied bool
+		onMeta bool
+	}{
+		{name: "document property", key: "id", value: "cookie", denied: true},
+		{name: "form property", key: "name", value: " submit ", denied: true},
+		{name: "application id", key: "id", value: "app-root", denied: true},
+		{name: "case sensitive", key: "id", value: "Cookie"},
+		{name: "regular id", key: "id", value: "section"},
+		{name: "other attribute", key: "title", value: "cookie"},
+		{name: "meta name", key: "name", value: "title", onMeta: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tag := &sanitize.Tag{}
+			if tt.onMeta {
+				tag.SetData("meta")
+			}
+			tag.UpsertAttr("", tt.key, tt.value)
+
+			sanitize.BlockClobbering("app-root").Apply(tag)
+
+			require.Equal(t, tt.denied, tag.Attrs()[0].IsDenied())
+		})
+	}
+}
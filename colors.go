@@ -0,0 +1,378 @@
+package sanitize
+
+import (
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// namedColors maps the CSS named colors to their hex values, including transparent,
+// and currentcolor, which has no value.
+var namedColors = map[string]string{
+	"aliceblue": "#f0f8ff", "antiquewhite": "#faebd7", "aqua": "#00ffff", "aquamarine": "#7fffd4",
+	"azure": "#f0ffff", "beige": "#f5f5dc", "bisque": "#ffe4c4", "black": "#000000",
+	"blanchedalmond": "#ffebcd", "blue": "#0000ff", "blueviolet": "#8a2be2", "brown": "#a52a2a",
+	"burlywood": "#deb887", "cadetblue": "#5f9ea0", "chartreuse": "#7fff00", "chocolate": "#d2691e",
+	"coral": "#ff7f50", "cornflowerblue": "#6495ed", "cornsilk": "#fff8dc", "crimson": "#dc143c",
+	"currentcolor": "", "cyan": "#00ffff", "darkblue": "#00008b", "darkcyan": "#008b8b",
+	"darkgoldenrod": "#b8860b", "darkgray": "#a9a9a9", "darkgreen": "#006400", "darkgrey": "#a9a9a9",
+	"darkkhaki": "#bdb76b", "darkmagenta": "#8b008b", "darkolivegreen": "#556b2f",
+	"darkorange": "#ff8c00", "darkorchid": "#9932cc", "darkred": "#8b0000", "darksalmon": "#e9967a",
+	"darkseagreen": "#8fbc8f", "darkslateblue": "#483d8b", "darkslategray": "#2f4f4f",
+	"darkslategrey": "#2f4f4f", "darkturquoise": "#00ced1", "darkviolet": "#9400d3",
+	"deeppink": "#ff1493", "deepskyblue": "#00bfff", "dimgray": "#696969", "dimgrey": "#696969",
+	"dodgerblue": "#1e90ff", "firebrick": "#b22222", "floralwhite": "#fffaf0",
+	"forestgreen": "#228b22", "fuchsia": "#ff00ff", "gainsboro": "#dcdcdc", "ghostwhite": "#f8f8ff",
+	"gold": "#ffd700", "goldenrod": "#daa520", "gray": "#808080", "green": "#008000",
+	"greenyellow": "#adff2f", "grey": "#808080", "honeydew": "#f0fff0", "hotpink": "#ff69b4",
+	"indianred": "#cd5c5c", "indigo": "#4b0082", "ivory": "#fffff0", "khaki": "#f0e68c",
+	"lavender": "#e6e6fa", "lavenderblush": "#fff0f5", "lawngreen": "#7cfc00",
+	"lemonchiffon": "#fffacd", "lightblue": "#add8e6", "lightcoral": "#f08080",
+	"lightcyan": "#e0ffff", "lightgoldenrodyellow": "#fafad2", "lightgray": "#d3d3d3",
+	"lightgreen": "#90ee90", "lightgrey": "#d3d3d3", "lightpink": "#ffb6c1", "lightsalmon": "#ffa07a",
+	"lightseagreen": "#20b2aa", "lightskyblue": "#87cefa", "lightslategray": "#778899",
+	"lightslategrey": "#778899", "lightsteelblue": "#b0c4de", "lightyellow": "#ffffe0",
+	"lime": "#00ff00", "limegreen": "#32cd32", "linen": "#faf0e6", "magenta": "#ff00ff",
+	"maroon": "#800000", "mediumaquamarine": "#66cdaa", "mediumblue": "#0000cd",
+	"mediumorchid": "#ba55d3", "mediumpurple": "#9370db", "mediumseagreen": "#3cb371",
+	"mediumslateblue": "#7b68ee", "mediumspringgreen": "#00fa9a", "mediumturquoise": "#48d1cc",
+	"mediumvioletred": "#c71585", "midnightblue": "#191970", "mintcream": "#f5fffa",
+	"mistyrose": "#ffe4e1", "moccasin": "#ffe4b5", "navajowhite": "#ffdead", "navy": "#000080",
+	"oldlace": "#fdf5e6", "olive": "#808000", "olivedrab": "#6b8e23", "orange": "#ffa500",
+	"orangered": "#ff4500", "orchid": "#da70d6", "palegoldenrod": "#eee8aa", "palegreen": "#98fb98",
+	"paleturquoise": "#afeeee", "palevioletred": "#db7093", "papayawhip": "#ffefd5",
+	"peachpuff": "#ffdab9", "peru": "#cd853f", "pink": "#ffc0cb", "plum": "#dda0dd",
+	"powderblue": "#b0e0e6", "purple": "#800080", "rebeccapurple": "#663399", "red": "#ff0000",
+	"rosybrown": "#bc8f8f", "royalblue": "#4169e1", "saddlebrown": "#8b4513", "salmon": "#fa8072",
+	"sandybrown": "#f4a460", "seagreen": "#2e8b57", "seashell": "#fff5ee", "sienna": "#a0522d",
+	"silver": "#c0c0c0", "skyblue": "#87ceeb", "slateblue": "#6a5acd", "slategray": "#708090",
+	"slategrey": "#708090", "snow": "#fffafa", "springgreen": "#00ff7f", "steelblue": "#4682b4",
+	"tan": "#d2b48c", "teal": "#008080", "thistle": "#d8bfd8", "tomato": "#ff6347",
+	"transparent": "#00000000", "turquoise": "#40e0d0", "violet": "#ee82ee", "wheat": "#f5deb3",
+	"white": "#ffffff", "whitesmoke": "#f5f5f5", "yellow": "#ffff00", "yellowgreen": "#9acd32",
+}
+
+// colorAttrs are the normalized keys of attributes containing a single color.
+var colorAttrs = map[string]struct{}{
+	"alink":   {},
+	"bgcolor": {},
+	"color":   {},
+	"link":    {},
+	"text":    {},
+	"vlink":   {},
+	// Allowed as attributes by WhitelistEmailAttrs.
+	"background-color":    {},
+	"border-bottom-color": {},
+	"border-color":        {},
+	"border-left-color":   {},
+	"border-right-color":  {},
+	"border-top-color":    {},
+}
+
+// colorProperties are the CSS properties containing colors.
+// Border colors can contain one color for each side.
+var colorProperties = map[string]struct{}{
+	"background-color":      {},
+	"border-bottom-color":   {},
+	"border-color":          {},
+	"border-left-color":     {},
+	"border-right-color":    {},
+	"border-top-color":      {},
+	"caret-color":           {},
+	"color":                 {},
+	"column-rule-color":     {},
+	"outline-color":         {},
+	"text-decoration-color": {},
+}
+
+// ParseColor validates a CSS color, returning it trimmed and lower cased. Valid colors are:
+//   - Named colors, like red, transparent or currentcolor.
+//   - Hex colors, like #fff, #ffff, #ffffff or #ffffffff.
+//   - rgb(), rgba(), hsl() and hsla() functions, with comma or space separated arguments.
+//
+// Anything else, like other functions, variables or keywords, is rejected.
+func ParseColor(value string) (string, bool) {
+	value = strings.ToLower(strings.TrimSpace(value))
+
+	if _, ok := namedColors[value]; ok {
+		return value, true
+	}
+
+	if hex, ok := strings.CutPrefix(value, "#"); ok {
+		switch len(hex) {
+		case 3, 4, 6, 8:
+			_, err := strconv.ParseUint(hex, 16, 32)
+			return value, err == nil
+		}
+		return "", false
+	}
+
+	name, args, ok := strings.Cut(value, "(")
+	if !ok || !strings.HasSuffix(args, ")") {
+		return "", false
+	}
+
+	if !isColorFunction(name, strings.TrimSuffix(args, ")")) {
+		return "", false
+	}
+
+	return value, true
+}
+
+// ValidateColors validates the values of color attributes, like bgcolor, color or text, and of
+// color properties in style attributes, like color or background-color.
+// It accepts keys as additional color attributes to be validated.
+//
+// Attributes with invalid colors are denied, and style declarations with invalid colors are removed.
+// Bare hex colors in attributes, like bgcolor="ffffff", are prefixed with #.
+func ValidateColors(keys ...string) Policy {
+	set := make(map[string]struct{}, len(colorAttrs)+len(keys))
+
+	for key := range colorAttrs {
+		set[key] = struct{}{}
+	}
+
+	for _, key := range keys {
+		set[Normalize(key)] = struct{}{}
+	}
+
+	return AttributePolicy(func(attr *Attribute) {
+		if attr.Key() == "style" {
+			decls, escaped := parseStyle(attr.value)
+			kept := slices.DeleteFunc(slices.Clone(decls), func(decl styleDeclaration) bool {
+				return !isValidStyleColor(decl)
+			})
+			if escaped || len(kept) != len(decls) {
+				attr.SetValue(renderStyle(kept))
+			}
+			return
+		}
+
+		if _, ok := set[attr.Key()]; !ok {
+			return
+		}
+
+		color, ok := parseLegacyColor(attr.value)
+		if !ok {
+			attr.Deny()
+			return
+		}
+		attr.SetValue(color)
+	})
+}
+
+// parseLegacyColor parses colors from presentational attributes, adding the # prefix to bare hex colors.
+func parseLegacyColor(value string) (string, bool) {
+	value = strings.TrimSpace(value)
+
+	if len(value) == 3 || len(value) == 6 {
+		if _, err := strconv.ParseUint(value, 16, 32); err == nil {
+			value = "#" + value
+		}
+	}
+
+	return ParseColor(value)
+}
+
+// isValidStyleColor checks if the declaration has a valid color, or is not a color property.
+func isValidStyleColor(decl styleDeclaration) bool {
+	if _, ok := colorProperties[decl.property]; !ok {
+		return true
+	}
+
+	colors := slices.DeleteFunc(splitCSS(decl.value, ' '), func(color string) bool {
+		return strings.TrimSpace(color) == ""
+	})
+
+	limit := 1
+	if decl.property == "border-color" {
+		limit = 4
+	}
+
+	if len(colors) == 0 || len(colors) > limit {
+		return false
+	}
+
+	for _, color := range colors {
+		if _, ok := ParseColor(color); !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isColorFunction validates the arguments of rgb(), rgba(), hsl() and hsla() functions.
+func isColorFunction(name, args string) bool {
+	channels, alpha, hasAlpha := splitColorArgs(args)
+
+	if len(channels) != 3 || (hasAlpha && !isColorNumber(alpha, "%")) {
+		return false
+	}
+
+	switch name {
+	case "rgb", "rgba":
+		for _, channel := range channels {
+			if !isColorNumber(channel, "%") {
+				return false
+			}
+		}
+		return true
+	case "hsl", "hsla":
+		return isColorNumber(channels[0], "deg", "rad", "grad", "turn") &&
+			isColorNumber(channels[1], "%") &&
+			isColorNumber(channels[2], "%")
+	default:
+		return false
+	}
+}
+
+// splitColorArgs splits the arguments of a color function into its channels and alpha,
+// with comma or space separated arguments, like rgb(0, 0, 0, 0.5) or rgb(0 0 0 / 50%).
+func splitColorArgs(args string) ([]string, string, bool) {
+	if strings.Contains(args, ",") {
+		channels := strings.Split(args, ",")
+		if len(channels) == 4 {
+			return channels[:3], channels[3], true
+		}
+		return channels, "", false
+	}
+
+	values, alpha, hasAlpha := strings.Cut(args, "/")
+	return strings.Fields(values), alpha, hasAlpha
+}
+
+// colorRGBA converts a valid color into its red, green, blue and alpha channels, so the same color
+// in different notations, like white, #fff or rgb(255 255 255), can be compared.
+// It returns false for invalid colors, and currentcolor.
+func colorRGBA(value string) ([4]uint8, bool) {
+	color, ok := ParseColor(value)
+	if !ok {
+		return [4]uint8{}, false
+	}
+
+	if hex, ok := namedColors[color]; ok {
+		color = hex
+	}
+
+	if hex, ok := strings.CutPrefix(color, "#"); ok {
+		return hexRGBA(hex)
+	}
+
+	name, args, ok := strings.Cut(strings.TrimSuffix(color, ")"), "(")
+	if !ok {
+		return [4]uint8{}, false
+	}
+
+	channels, alpha, hasAlpha := splitColorArgs(args)
+
+	rgba := [4]uint8{3: 0xff}
+	if hasAlpha {
+		n, unit := parseColorNumber(alpha, "%")
+		if unit == "%" {
+			n /= 100
+		}
+		rgba[3] = colorChannel(n * 0xff)
+	}
+
+	switch name {
+	case "rgb", "rgba":
+		for i, channel := range channels {
+			n, unit := parseColorNumber(channel, "%")
+			if unit == "%" {
+				n *= 0xff / 100.0
+			}
+			rgba[i] = colorChannel(n)
+		}
+	case "hsl", "hsla":
+		hue, unit := parseColorNumber(channels[0], "deg", "rad", "grad", "turn")
+		switch unit {
+		case "rad":
+			hue *= 180 / math.Pi
+		case "grad":
+			hue *= 0.9
+		case "turn":
+			hue *= 360
+		}
+
+		saturation, _ := parseColorNumber(channels[1], "%")
+		lightness, _ := parseColorNumber(channels[2], "%")
+		saturation = min(max(saturation/100, 0), 1)
+		lightness = min(max(lightness/100, 0), 1)
+
+		a := saturation * min(lightness, 1-lightness)
+		for i, n := range [3]float64{0, 8, 4} {
+			k := math.Mod(n+hue/30, 12)
+			if k < 0 {
+				k += 12
+			}
+			rgba[i] = colorChannel((lightness - a*max(-1, min(k-3, 9-k, 1))) * 0xff)
+		}
+	}
+
+	return rgba, true
+}
+
+// hexRGBA converts the digits of a hex color, with 3, 4, 6 or 8 digits, into its channels.
+func hexRGBA(hex string) ([4]uint8, bool) {
+	if len(hex) == 3 || len(hex) == 4 {
+		var b strings.Builder
+		for i := range len(hex) {
+			b.WriteByte(hex[i])
+			b.WriteByte(hex[i])
+		}
+		hex = b.String()
+	}
+
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+
+	n, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil || len(hex) != 8 {
+		return [4]uint8{}, false
+	}
+
+	return [4]uint8{uint8(n >> 24), uint8(n >> 16), uint8(n >> 8), uint8(n)}, true
+}
+
+// parseColorNumber parses a number validated by isColorNumber, returning its unit, if any.
+func parseColorNumber(value string, units ...string) (float64, string) {
+	value = strings.TrimSpace(value)
+
+	var unit string
+	for _, u := range units {
+		if number, ok := strings.CutSuffix(value, u); ok {
+			value, unit = number, u
+			break
+		}
+	}
+
+	n, _ := strconv.ParseFloat(value, 64)
+	return n, unit
+}
+
+// colorChannel rounds and clamps a channel value to a byte.
+func colorChannel(n float64) uint8 {
+	return uint8(math.Round(min(max(n, 0), 0xff)))
+}
+
+// isColorNumber checks if the value is a decimal number, optionally followed by one of the units.
+func isColorNumber(value string, units ...string) bool {
+	value = strings.TrimSpace(value)
+
+	for _, unit := range units {
+		if number, ok := strings.CutSuffix(value, unit); ok {
+			value = number
+			break
+		}
+	}
+
+	if value == "" || strings.Trim(value, "0123456789.+-") != "" {
+		return false
+	}
+
+	_, err := strconv.ParseFloat(value, 64)
+	return err == nil
+}
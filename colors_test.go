@@ -0,0 +1,91 @@
+package sanitize_test
+
+import (
+	"testing"
+
+	"github.com/sonalys/sanitize"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseColor(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+		valid bool
+	}{
+		{value: "Red", want: "red", valid: true},
+		{value: " transparent ", want: "transparent", valid: true},
+		{value: "currentColor", want: "currentcolor", valid: true},
+		{value: "#FFF", want: "#fff", valid: true},
+		{value: "#ffff", want: "#ffff", valid: true},
+		{value: "#a0b1c2", want: "#a0b1c2", valid: true},
+		{value: "#a0b1c2d3", want: "#a0b1c2d3", valid: true},
+		{value: "rgb(255, 0, 0)", want: "rgb(255, 0, 0)", valid: true},
+		{value: "rgba(100%,0%,0%,0.5)", want: "rgba(100%,0%,0%,0.5)", valid: true},
+		{value: "rgb(255 0 0 / 50%)", want: "rgb(255 0 0 / 50%)", valid: true},
+		{value: "hsl(120deg, 100%, 50%)", want: "hsl(120deg, 100%, 50%)", valid: true},
+		{value: "hsla(0.5turn 100% 50% / .5)", want: "hsla(0.5turn 100% 50% / .5)", valid: true},
+		{value: "ffffff"},
+		{value: "#ffg"},
+		{value: "#fffff"},
+		{value: "notacolor"},
+		{value: "var(--color)"},
+		{value: "rgb(255, 0)"},
+		{value: "rgb(255, 0, 0,)"},
+		{value: "rgb(255 0 0 /)"},
+		{value: "rgb(0x10, 0, 0)"},
+		{value: "rgb(inf, 0, 0)"},
+		{value: "hsl(120%, 100%, 50%)"},
+		{value: "url(a.png)"},
+		{value: "red;position:fixed"},
+		{value: "expression(alert(1))"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, valid := sanitize.ParseColor(tt.value)
+			require.Equal(t, tt.valid, valid)
+			if tt.valid {
+				require.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_ValidateColors(t *testing.T) {
+	t.Run("should validate color attributes", func(t *testing.T) {
+		tag := &sanitize.Tag{}
+		tag.UpsertAttr("", "bgcolor", "ffffff")
+		tag.UpsertAttr("", "color", "javascript:alert(1)")
+		tag.UpsertAttr("", "border-color", "RED")
+		tag.UpsertAttr("", "fill", "evil")
+		tag.UpsertAttr("", "title", "not a color")
+
+		sanitize.ValidateColors("fill").Apply(tag)
+
+		attrs := tag.Attrs()
+		require.Equal(t, "#ffffff", attrs[0].Value())
+		require.True(t, attrs[1].IsDenied())
+		require.Equal(t, "red", attrs[2].Value())
+		require.True(t, attrs[3].IsDenied())
+		require.False(t, attrs[4].IsBlocked())
+	})
+
+	t.Run("should remove invalid style colors", func(t *testing.T) {
+		tag := &sanitize.Tag{}
+		tag.UpsertAttr("", "style", "color:red;background-color:var(--x);border-color:red rgb(0 0 0) #fff;width:10px")
+
+		sanitize.ValidateColors().Apply(tag)
+
+		require.Equal(t, "color:red;border-color:red rgb(0 0 0) #fff;width:10px", tag.Attrs()[0].Value())
+	})
+
+	t.Run("should be enforced by AllowStyles", func(t *testing.T) {
+		tag := &sanitize.Tag{}
+		tag.UpsertAttr("", "style", "color:red blue;background-color:#000")
+
+		sanitize.AllowStyles("color", "background-color").Apply(tag)
+
+		require.Equal(t, "background-color:#000", tag.Attrs()[0].Value())
+	})
+}
@@ -0,0 +1,297 @@
+package sanitize
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/html/atom"
+	"golang.org/x/net/idna"
+	"golang.org/x/text/unicode/norm"
+)
+
+type (
+	// ConfusableFinding describes a host that can be confused with another one.
+	ConfusableFinding struct {
+		// Host is the host in its unicode form, as seen by the user.
+		Host string
+		// Key is the normalized key of the attribute containing the host,
+		// or empty when the host was found in a link's visible text.
+		Key string
+		// Lookalike is the protected domain imitated by the host, if any.
+		Lookalike string
+		// MixedScript is set when a label of the host mixes letters from different scripts.
+		MixedScript bool
+	}
+
+	// ConfusableHandler handles confusable hosts found by DetectConfusables.
+	ConfusableHandler func(tag *Tag, finding ConfusableFinding)
+)
+
+// confusables maps characters to the lower case Latin letters they can be confused with.
+// It's a hand-picked list of common lookalikes, not the full Unicode confusables table.
+var confusables = map[rune]string{
+	// Digits and Latin.
+	'0': "o",
+	'1': "l",
+	'ı': "i",
+	'ɑ': "a",
+	'ɡ': "g",
+	'ɩ': "i",
+	'ʏ': "y",
+	'ℓ': "l",
+	// Cyrillic.
+	'а': "a",
+	'е': "e",
+	'һ': "h",
+	'і': "i",
+	'ј': "j",
+	'ӏ': "l",
+	'о': "o",
+	'р': "p",
+	'с': "c",
+	'ѕ': "s",
+	'у': "y",
+	'ү': "y",
+	'х': "x",
+	'ԁ': "d",
+	'ԛ': "q",
+	'ԝ': "w",
+	// Greek.
+	'α': "a",
+	'ι': "i",
+	'κ': "k",
+	'ν': "v",
+	'ο': "o",
+	'ρ': "p",
+	'υ': "u",
+	'χ': "x",
+	'ϲ': "c",
+	'ϳ': "j",
+	// Armenian.
+	'հ': "h",
+	'ո': "n",
+	'ս': "u",
+	'ց': "g",
+	'զ': "q",
+	'օ': "o",
+}
+
+// confusableSequences replaces letter sequences with the single letter they can be confused with.
+var confusableSequences = strings.NewReplacer(
+	"rn", "m",
+	"cl", "d",
+	"vv", "w",
+)
+
+// compatibleScripts are script combinations commonly used together, which are not considered mixed.
+var compatibleScripts = [][]string{
+	{"Latin", "Han", "Hiragana", "Katakana"},
+	{"Latin", "Han", "Hangul"},
+	{"Latin", "Han", "Bopomofo"},
+}
+
+// FoldConfusables replaces lookalike characters and sequences with the Latin letters they can be
+// confused with, like the Cyrillic а with a, 0 with o, or rn with m. Two strings are confusable
+// if they fold into the same text, like paypal.com and pаypal.com, with a Cyrillic а.
+//
+// It covers common lookalikes of Latin letters, not the whole Unicode confusables table.
+// Folded text is lower cased, and is not meant to be displayed.
+func FoldConfusables(s string) string {
+	s = norm.NFD.String(strings.ToLower(s))
+
+	var b strings.Builder
+	for _, r := range s {
+		// Fullwidth forms are equivalent to ASCII.
+		if r >= 0xFF01 && r <= 0xFF5E {
+			r -= 0xFEE0
+		}
+
+		if prototype, ok := confusables[r]; ok {
+			b.WriteString(prototype)
+			continue
+		}
+		b.WriteRune(r)
+	}
+
+	return norm.NFD.String(confusableSequences.Replace(b.String()))
+}
+
+// Confusable checks if the strings can be confused with each other.
+func Confusable(a, b string) bool {
+	return FoldConfusables(a) == FoldConfusables(b)
+}
+
+// IsMixedScript checks if any word of the text mixes letters from different scripts,
+// like Latin and Cyrillic. Words are separated by spaces, dots and other punctuation.
+//
+// Common combinations, like Japanese using Latin, Han, Hiragana and Katakana, are not considered mixed.
+func IsMixedScript(s string) bool {
+	words := strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsMark(r) && !unicode.IsDigit(r)
+	})
+
+	for _, word := range words {
+		if isMixedScriptWord(word) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsConfusableHost checks if the host is mixed-script, or confusable with any of the given domains
+// without being equal to it. Hosts can be in their unicode or punycode form.
+func IsConfusableHost(host string, domains ...string) bool {
+	protected := make([]string, 0, len(domains))
+	for _, domain := range domains {
+		protected = append(protected, unicodeHost(domain))
+	}
+
+	finding := inspectHost(unicodeHost(host), protected)
+	return finding.MixedScript || finding.Lookalike != ""
+}
+
+// DetectConfusables inspects all URL attributes and the visible text of links for confusable hosts,
+// calling the handler for any host that:
+//   - Mixes letters from different scripts in the same label, like pаypal.com, with a Cyrillic а.
+//   - Is confusable with one of the given protected domains, without being equal to it.
+//
+// Example:
+//
+//	sanitize.DetectConfusables(report, "paypal.com", "mybank.com")
+func DetectConfusables(handler ConfusableHandler, domains ...string) Policy {
+	protected := make([]string, 0, len(domains))
+	for _, domain := range domains {
+		protected = append(protected, unicodeHost(domain))
+	}
+
+	return TagPolicy(func(tag *Tag) {
+		for _, attr := range tag.attributes {
+			if _, ok := urlAttrs[attr.Key()]; !ok {
+				continue
+			}
+
+			u, err := parseURL(attr.value)
+			if err != nil || u.Hostname() == "" {
+				continue
+			}
+
+			finding := inspectHost(unicodeHost(u.Hostname()), protected)
+			if finding.MixedScript || finding.Lookalike != "" {
+				finding.Key = attr.Key()
+				handler(tag, finding)
+			}
+		}
+
+		if tag.atom != atom.A {
+			return
+		}
+
+		if host, ok := hostFromText(strings.TrimSpace(tag.Text())); ok {
+			finding := inspectHost(unicodeHost(host), protected)
+			if finding.MixedScript || finding.Lookalike != "" {
+				handler(tag, finding)
+			}
+		}
+	})
+}
+
+func inspectHost(host string, protected []string) ConfusableFinding {
+	finding := ConfusableFinding{
+		Host:        host,
+		MixedScript: IsMixedScript(host),
+	}
+
+	folded := FoldConfusables(host)
+	for _, domain := range protected {
+		if domain != host && FoldConfusables(domain) == folded {
+			finding.Lookalike = domain
+			break
+		}
+	}
+
+	return finding
+}
+
+// unicodeHost converts the host into its normalized unicode form.
+func unicodeHost(host string) string {
+	host = normalizeHost(host)
+	if u, err := idna.Lookup.ToUnicode(host); err == nil {
+		return u
+	}
+	return host
+}
+
+func isMixedScriptWord(word string) bool {
+	scripts := make(map[string]struct{})
+
+	for _, r := range word {
+		if name := scriptOf(r); name != "" {
+			scripts[name] = struct{}{}
+		}
+	}
+
+	if len(scripts) <= 1 {
+		return false
+	}
+
+	for _, compatible := range compatibleScripts {
+		matches := 0
+		for _, name := range compatible {
+			if _, ok := scripts[name]; ok {
+				matches++
+			}
+		}
+		if matches == len(scripts) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// letterScripts are the scripts checked for letters, ordered by how common they are in hosts and text.
+var letterScripts = []struct {
+	name  string
+	table *unicode.RangeTable
+}{
+	{"Latin", unicode.Latin},
+	{"Cyrillic", unicode.Cyrillic},
+	{"Greek", unicode.Greek},
+	{"Han", unicode.Han},
+	{"Hiragana", unicode.Hiragana},
+	{"Katakana", unicode.Katakana},
+	{"Hangul", unicode.Hangul},
+	{"Bopomofo", unicode.Bopomofo},
+	{"Arabic", unicode.Arabic},
+	{"Hebrew", unicode.Hebrew},
+	{"Armenian", unicode.Armenian},
+	{"Georgian", unicode.Georgian},
+	{"Devanagari", unicode.Devanagari},
+	{"Bengali", unicode.Bengali},
+	{"Tamil", unicode.Tamil},
+	{"Telugu", unicode.Telugu},
+	{"Thai", unicode.Thai},
+	{"Lao", unicode.Lao},
+	{"Khmer", unicode.Khmer},
+	{"Myanmar", unicode.Myanmar},
+	{"Ethiopic", unicode.Ethiopic},
+	{"Cherokee", unicode.Cherokee},
+	{"Coptic", unicode.Coptic},
+}
+
+// scriptOf returns the script name of a letter, or empty for common and inherited characters.
+// Letters of scripts missing from letterScripts are reported as Other.
+func scriptOf(r rune) string {
+	if !unicode.IsLetter(r) || unicode.Is(unicode.Common, r) || unicode.Is(unicode.Inherited, r) {
+		return ""
+	}
+
+	for _, script := range letterScripts {
+		if unicode.Is(script.table, r) {
+			return script.name
+		}
+	}
+
+	return "Other"
+}
@@ -0,0 +1,59 @@
+package sanitize_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/sonalys/sanitize"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_FoldConfusables(t *testing.T) {
+	require.Equal(t, "modem.example", sanitize.FoldConfusables("rnodern.example"))
+	require.Equal(t, "dowd.example", sanitize.FoldConfusables("clovvԁ.example"))
+	require.Equal(t, "apple", sanitize.FoldConfusables("ａрр1е"))
+}
+
+func Test_Confusable(t *testing.T) {
+	require.True(t, sanitize.Confusable("paypal.com", "pаypal.com"))
+	require.True(t, sanitize.Confusable("PAYPAL.COM", "pаypаl.cоm"))
+	require.True(t, sanitize.Confusable("modern.example", "rnodern.example"))
+	require.True(t, sanitize.Confusable("google.com", "g00gle.com"))
+	require.True(t, sanitize.Confusable("dropbox.com", "clropbox.com"))
+	require.False(t, sanitize.Confusable("paypal.com", "paypa1.org"))
+}
+
+func Test_IsMixedScript(t *testing.T) {
+	require.True(t, sanitize.IsMixedScript("pаypal.com"))
+	require.False(t, sanitize.IsMixedScript("paypal.com"))
+	require.False(t, sanitize.IsMixedScript("пример.com"))
+	require.False(t, sanitize.IsMixedScript("日本語のテキストabc"))
+	require.True(t, sanitize.IsMixedScript("normal text with а cyrillic wоrd"))
+	require.True(t, sanitize.IsMixedScript("exаmрlе"))
+	require.True(t, sanitize.IsMixedScript("gοogle"))
+}
+
+func Test_IsConfusableHost(t *testing.T) {
+	require.True(t, sanitize.IsConfusableHost("xn--pypal-4ve.com"))
+	require.True(t, sanitize.IsConfusableHost("paypa1.com", "paypal.com"))
+	require.False(t, sanitize.IsConfusableHost("paypal.com", "paypal.com"))
+	require.False(t, sanitize.IsConfusableHost("example.com", "paypal.com"))
+}
+
+func Test_DetectConfusables(t *testing.T) {
+	var findings []sanitize.ConfusableFinding
+
+	content := []byte(`<a href="https://xn--pypal-4ve.com/">paypa1.com</a><img src="https://cdn.example/a.png"/>`)
+	out := bytes.NewBuffer(make([]byte, 0, 1024))
+	err := sanitize.HTML(bytes.NewReader(content), out,
+		sanitize.DetectConfusables(func(_ *sanitize.Tag, finding sanitize.ConfusableFinding) {
+			findings = append(findings, finding)
+		}, "paypal.com"),
+	)
+	require.NoError(t, err)
+
+	require.Equal(t, []sanitize.ConfusableFinding{
+		{Host: "pаypal.com", Key: "href", Lookalike: "paypal.com", MixedScript: true},
+		{Host: "paypa1.com", Lookalike: "paypal.com"},
+	}, findings)
+}
@@ -0,0 +1,248 @@
+package sanitize
+
+import (
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+// domainPattern is a parsed domain from AllowDomains or BlockDomains.
+type domainPattern struct {
+	host     string
+	port     string
+	wildcard bool
+}
+
+// AllowDomains will deny all URLs with hosts not matching any of the given domains.
+// It applies to all URL attributes, srcset candidates and url() in style attributes.
+//
+// Domains support:
+//   - Wildcard subdomains, *.example.com matches sub.example.com, but not example.com.
+//   - Internationalized domains, which are compared in their punycode form.
+//   - Ports, example.com:8080 only matches the given port, while example.com matches any port.
+//   - IP literals, like 127.0.0.1 or [::1], which are never matched by wildcards.
+//
+// URLs without a host, like relative or cid: URLs, are not affected. URLs with schemes that always have
+// a host, like http or https, are read the way browsers do, like http:evil.com or https:/evil.com,
+// and denied if they have no host.
+func AllowDomains(domains ...string) Policy {
+	patterns := parseDomainPatterns(domains)
+
+	return RewriteURLs(func(rawURL string) (string, bool) {
+		u, err := parseURL(rawURL)
+		if err != nil {
+			return "", false
+		}
+		u, ok := urlHost(u)
+		if !ok {
+			return "", false
+		}
+		if u.Host == "" {
+			return rawURL, true
+		}
+
+		return rawURL, matchDomains(patterns, u)
+	})
+}
+
+// BlockDomains will deny all URLs with hosts matching any of the given domains.
+// It applies to all URL attributes, srcset candidates and url() in style attributes.
+//
+// Domains are matched the same way as AllowDomains.
+func BlockDomains(domains ...string) Policy {
+	patterns := parseDomainPatterns(domains)
+
+	return RewriteURLs(func(rawURL string) (string, bool) {
+		u, err := parseURL(rawURL)
+		if err != nil {
+			return "", false
+		}
+		u, ok := urlHost(u)
+		if !ok {
+			return "", false
+		}
+		if u.Host == "" {
+			return rawURL, true
+		}
+
+		return rawURL, !matchDomains(patterns, u)
+	})
+}
+
+// urlHost returns the URL with the host browsers would read for schemes that always have one,
+// like http:evil.com or https:/evil.com, which are parsed without a host. It returns false if there is none.
+func urlHost(u *url.URL) (*url.URL, bool) {
+	if u.Host != "" || defaultPort(u.Scheme) == "" {
+		return u, true
+	}
+
+	rest := u.Opaque
+	if rest == "" {
+		rest = u.Path
+	}
+
+	host, err := url.Parse(strings.ToLower(u.Scheme) + "://" + strings.TrimLeft(rest, "/"))
+	if err != nil || host.Host == "" {
+		return nil, false
+	}
+
+	host.RawQuery = u.RawQuery
+	host.Fragment = u.Fragment
+
+	return host, true
+}
+
+func parseDomainPatterns(domains []string) []domainPattern {
+	patterns := make([]domainPattern, 0, len(domains))
+
+	for _, domain := range domains {
+		var pattern domainPattern
+
+		domain = strings.TrimSpace(domain)
+		if rest, ok := strings.CutPrefix(domain, "*."); ok {
+			pattern.wildcard = true
+			domain = rest
+		}
+
+		host, port, err := net.SplitHostPort(domain)
+		if err != nil {
+			host = strings.Trim(domain, "[]")
+		}
+
+		pattern.host = normalizeHost(host)
+		pattern.port = port
+		patterns = append(patterns, pattern)
+	}
+
+	return patterns
+}
+
+func matchDomains(patterns []domainPattern, u *url.URL) bool {
+	host := normalizeHost(u.Hostname())
+	port := u.Port()
+	if port == "" {
+		port = defaultPort(u.Scheme)
+	}
+
+	for _, pattern := range patterns {
+		if pattern.port != "" && pattern.port != port {
+			continue
+		}
+
+		if pattern.wildcard {
+			if !isIPHost(host) && strings.HasSuffix(host, "."+pattern.host) {
+				return true
+			}
+			continue
+		}
+
+		if host == pattern.host {
+			return true
+		}
+	}
+
+	return false
+}
+
+// normalizeHost lower cases the host, removes the trailing dot and converts
+// internationalized domains into punycode. IP literals are returned in their canonical form,
+// including IPv4 hosts in the numeric forms accepted by browsers, like 2130706433 or 0x7f.1.
+func normalizeHost(host string) string {
+	host = strings.TrimSuffix(strings.ToLower(strings.Trim(strings.TrimSpace(host), "[]")), ".")
+
+	if ip := net.ParseIP(host); ip != nil {
+		return ip.String()
+	}
+
+	if ip, ok := parseIPv4(host); ok {
+		return ip.String()
+	}
+
+	if ascii, err := idna.Lookup.ToASCII(host); err == nil {
+		return ascii
+	}
+
+	return host
+}
+
+// isIPHost checks if the host would be interpreted by browsers as an IP address.
+// Besides IPv6 and dotted IPv4 addresses, it detects IPv4 hosts ending in a number,
+// like 2130706433, 0x7f.1 or 0177.0.0.1.
+func isIPHost(host string) bool {
+	host = strings.TrimSuffix(strings.Trim(host, "[]"), ".")
+
+	if net.ParseIP(host) != nil {
+		return true
+	}
+
+	labels := strings.Split(host, ".")
+	_, ok := parseIPv4Number(labels[len(labels)-1])
+	return ok
+}
+
+// parseIPv4 parses an IPv4 host the way browsers do, where each part can be decimal,
+// hexadecimal or octal, and the last part fills the remaining bytes.
+func parseIPv4(host string) (net.IP, bool) {
+	parts := strings.Split(host, ".")
+	if len(parts) > 4 {
+		return nil, false
+	}
+
+	var ip uint64
+	for i, part := range parts {
+		n, ok := parseIPv4Number(part)
+		if !ok {
+			return nil, false
+		}
+
+		if i < len(parts)-1 {
+			if n > 0xff {
+				return nil, false
+			}
+			ip |= n << (8 * (3 - i))
+			continue
+		}
+
+		if n >= 1<<(8*(5-len(parts))) {
+			return nil, false
+		}
+		ip |= n
+	}
+
+	return net.IPv4(byte(ip>>24), byte(ip>>16), byte(ip>>8), byte(ip)), true
+}
+
+func parseIPv4Number(part string) (uint64, bool) {
+	base := 10
+
+	switch {
+	case part == "":
+		return 0, false
+	case strings.HasPrefix(part, "0x") || strings.HasPrefix(part, "0X"):
+		part, base = part[2:], 16
+		if part == "" {
+			return 0, true
+		}
+	case len(part) > 1 && part[0] == '0':
+		part, base = part[1:], 8
+	}
+
+	n, err := strconv.ParseUint(part, base, 32)
+	return n, err == nil
+}
+
+func defaultPort(scheme string) string {
+	switch strings.ToLower(scheme) {
+	case "http", "ws":
+		return "80"
+	case "https", "wss":
+		return "443"
+	case "ftp":
+		return "21"
+	default:
+		return ""
+	}
+}
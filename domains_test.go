@@ -0,0 +1,60 @@
+package sanitize_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/sonalys/sanitize"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_AllowDomains(t *testing.T) {
+	policy := sanitize.AllowDomains("*.cdn.example", "example.com:8443", "bücher.example", "10.0.0.1")
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "wildcard", input: `<img src="https://img.CDN.example./a.png"/>`, expected: `<img src="https://img.CDN.example./a.png"/>`},
+		{name: "wildcard apex", input: `<img src="https://cdn.example/a.png"/>`, expected: `<img/>`},
+		{name: "port", input: `<img src="https://example.com:8443/a.png"/>`, expected: `<img src="https://example.com:8443/a.png"/>`},
+		{name: "default port", input: `<img src="https://example.com/a.png"/>`, expected: `<img/>`},
+		{name: "idn", input: `<a href="http://xn--bcher-kva.example/">a</a>`, expected: `<a href="http://xn--bcher-kva.example/">a</a>`},
+		{name: "unicode idn", input: `<a href="http://BÜCHER.example/">a</a>`, expected: `<a href="http://BÜCHER.example/">a</a>`},
+		{name: "ip", input: `<a href="http://10.0.0.1/">a</a>`, expected: `<a href="http://10.0.0.1/">a</a>`},
+		{name: "backslashes", input: `<a href="https:\\evil.com">a</a>`, expected: `<a>a</a>`},
+		{name: "missing slashes", input: `<img src="http:evil.com/a.png"/><img src="https:/evil.com/a.png"/><img src="https:img.cdn.example/a.png"/>`, expected: `<img/><img/><img src="https:img.cdn.example/a.png"/>`},
+		{name: "missing host", input: `<a href="https:">a</a><a href="http:///">b</a>`, expected: `<a>a</a><a>b</a>`},
+		{name: "relative", input: `<a href="/path">a</a><img src="cid:a"/>`, expected: `<a href="/path">a</a><img src="cid:a"/>`},
+		{name: "srcset", input: `<img srcset="https://a.cdn.example/1.png 1x, https://evil.com/2.png 2x"/>`, expected: `<img srcset="https://a.cdn.example/1.png 1x"/>`},
+		{name: "srcset empty", input: `<img srcset="https://evil.com/2.png 2x"/>`, expected: `<img/>`},
+		{name: "ping", input: `<a ping=" https://a.cdn.example/p  https://evil.com/p">a</a><a ping="https://evil.com/p">b</a>`, expected: `<a ping="https://a.cdn.example/p">a</a><a>b</a>`},
+		{name: "style", input: `<div style="color:red;background:URL('https://evil.com/a.png');background-image:url(https://a.cdn.example/b.png)"></div>`, expected: `<div style="color:red;background-image:url(&#34;https://a.cdn.example/b.png&#34;)"></div>`},
+		{name: "style escape", input: `<div style="color:red;background:u\72l(https://evil.com/a.png)"></div>`, expected: `<div style="color:red"></div>`},
+		{name: "style escaped property", input: `<div style="color:red;backgr\6fund:url(https://a.cdn.example/a.png)"></div>`, expected: `<div style="color:red"></div>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := sanitizeWith(t, tt.input, policy)
+			require.Equal(t, "<html><head></head><body>"+tt.expected+"</body></html>", got)
+		})
+	}
+
+	t.Run("should not be allowed by subsequent policies", func(t *testing.T) {
+		got := sanitizeWith(t, `<img src="https://evil.com/a.png"/>`, policy, sanitize.AllowAttrs("src"))
+		require.Equal(t, `<html><head></head><body><img/></body></html>`, got)
+	})
+}
+
+func Test_BlockDomains(t *testing.T) {
+	content := []byte(`<a href="https://evil.com">1</a><a href="https://sub.evil.com">2</a><a href="http://2130706433/">3</a><a href="https://good.com">4</a><a href="http:evil.com/">5</a><a href="https:/sub.evil.com/">6</a>`)
+	out := bytes.NewBuffer(make([]byte, 0, 1024))
+	err := sanitize.HTML(bytes.NewReader(content), out,
+		sanitize.BlockDomains("evil.com", "*.evil.com", "127.0.0.1"),
+	)
+	require.NoError(t, err)
+
+	require.Equal(t, `<html><head></head><body><a>1</a><a>2</a><a>3</a><a href="https://good.com">4</a><a>5</a><a>6</a></body></html>`, out.String())
+}
@@ -0,0 +1,211 @@
+package sanitize
+
+import (
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// editorRenames normalizes equivalent formatting tags produced by rich-text editors.
+var editorRenames = map[atom.Atom]atom.Atom{
+	atom.B:      atom.Strong,
+	atom.I:      atom.Em,
+	atom.Strike: atom.S,
+	atom.Del:    atom.S,
+}
+
+// blockAtoms are the tags that cannot be placed inside a paragraph.
+var blockAtoms = map[atom.Atom]struct{}{
+	atom.Address:    {},
+	atom.Article:    {},
+	atom.Aside:      {},
+	atom.Blockquote: {},
+	atom.Details:    {},
+	atom.Div:        {},
+	atom.Dl:         {},
+	atom.Fieldset:   {},
+	atom.Figure:     {},
+	atom.Footer:     {},
+	atom.Form:       {},
+	atom.H1:         {},
+	atom.H2:         {},
+	atom.H3:         {},
+	atom.H4:         {},
+	atom.H5:         {},
+	atom.H6:         {},
+	atom.Header:     {},
+	atom.Hr:         {},
+	atom.Main:       {},
+	atom.Nav:        {},
+	atom.Ol:         {},
+	atom.P:          {},
+	atom.Pre:        {},
+	atom.Section:    {},
+	atom.Table:      {},
+	atom.Ul:         {},
+}
+
+// TinyMCEPolicies allows the default TinyMCE content model:
+//
+//	Tags:       p, h1-h6, pre, blockquote, ul, ol, li, a, strong, em, u, s, sub, sup, span, code,
+//	            br, hr, img, figure, figcaption, table, caption, thead, tbody, tfoot, tr, th, td
+//	Attributes: href, target, title, src, alt, width, height, colspan, rowspan
+//	Styles:     color, background-color, text-align, text-decoration, padding-left, margin-left
+//
+// It normalizes b into strong, i into em, strike and del into s, and paragraph divs into p.
+// Divs containing other blocks, and tags outside the content model, are unwrapped.
+// TinyMCE bogus elements and data-mce-* attributes and mce-* classes are removed.
+func TinyMCEPolicies() Policy {
+	return editorPolicies(
+		TagPolicy(func(tag *Tag) {
+			switch bogus, ok := tag.attrValue("data-mce-bogus"); {
+			case !ok:
+			case bogus == "all":
+				tag.Deny()
+			default:
+				tag.Unwrap()
+			}
+
+			removeAttrPrefix(tag, "data-mce-")
+			filterClasses(tag, func(class string) (string, bool) {
+				return class, !strings.HasPrefix(class, "mce-")
+			})
+		}),
+		[]atom.Atom{
+			atom.P, atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6, atom.Pre, atom.Blockquote,
+			atom.Ul, atom.Ol, atom.Li, atom.A, atom.Strong, atom.Em, atom.U, atom.S, atom.Sub, atom.Sup,
+			atom.Span, atom.Code, atom.Br, atom.Hr, atom.Img, atom.Figure, atom.Figcaption,
+			atom.Table, atom.Caption, atom.Thead, atom.Tbody, atom.Tfoot, atom.Tr, atom.Th, atom.Td,
+		},
+		[]string{"href", "target", "title", "src", "alt", "width", "height", "colspan", "rowspan"},
+		"color", "background-color", "text-align", "text-decoration", "padding-left", "margin-left",
+	)
+}
+
+// QuillPolicies allows the Quill content model:
+//
+//	Tags:       p, h1-h6, pre, blockquote, ul, ol, li, a, strong, em, u, s, sub, sup, span, code, br, img
+//	Attributes: href, target, src, alt, dir
+//	Styles:     text-align, padding-left
+//
+// It normalizes b into strong, i into em, strike and del into s, and paragraph divs into p.
+// Divs containing other blocks, and tags outside the content model, are unwrapped.
+// Quill ql-align-*, ql-indent-* and ql-direction-rtl classes are converted into styles and the dir
+// attribute, other ql-* classes and Quill UI elements are removed.
+func QuillPolicies() Policy {
+	return editorPolicies(
+		TagPolicy(func(tag *Tag) {
+			filterClasses(tag, func(class string) (string, bool) {
+				switch {
+				case class == "ql-ui":
+					tag.Deny()
+				case class == "ql-direction-rtl":
+					tag.UpsertAttr("", "dir", "rtl")
+				case strings.HasPrefix(class, "ql-align-"):
+					tag.SetStyle("text-align", strings.TrimPrefix(class, "ql-align-"))
+				case strings.HasPrefix(class, "ql-indent-"):
+					if level, err := strconv.Atoi(strings.TrimPrefix(class, "ql-indent-")); err == nil && level > 0 {
+						tag.SetStyle("padding-left", strconv.Itoa(level*3)+"em")
+					}
+				case !strings.HasPrefix(class, "ql-"):
+					return class, true
+				}
+				return "", false
+			})
+		}),
+		[]atom.Atom{
+			atom.P, atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6, atom.Pre, atom.Blockquote,
+			atom.Ul, atom.Ol, atom.Li, atom.A, atom.Strong, atom.Em, atom.U, atom.S, atom.Sub, atom.Sup,
+			atom.Span, atom.Code, atom.Br, atom.Img,
+		},
+		[]string{"href", "target", "src", "alt", "dir"},
+		"text-align", "padding-left",
+	)
+}
+
+// ProseMirrorPolicies allows the ProseMirror basic schema content model:
+//
+//	Tags:       p, h1-h6, pre, blockquote, ul, ol, li, a, strong, em, code, br, hr, img
+//	Attributes: href, title, src, alt, start
+//
+// It normalizes b into strong, i into em, strike and del into s, and paragraph divs into p.
+// Divs containing other blocks, and tags outside the content model, are unwrapped.
+// ProseMirror-* classes and helper elements, like trailing breaks and separators, are removed.
+func ProseMirrorPolicies() Policy {
+	return editorPolicies(
+		TagPolicy(func(tag *Tag) {
+			filterClasses(tag, func(class string) (string, bool) {
+				switch class {
+				case "ProseMirror-trailingBreak", "ProseMirror-separator":
+					tag.Deny()
+				}
+				return class, !strings.HasPrefix(class, "ProseMirror")
+			})
+			removeAttrPrefix(tag, "data-pm-")
+		}),
+		[]atom.Atom{
+			atom.P, atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6, atom.Pre, atom.Blockquote,
+			atom.Ul, atom.Ol, atom.Li, atom.A, atom.Strong, atom.Em, atom.Code, atom.Br, atom.Hr, atom.Img,
+		},
+		[]string{"href", "title", "src", "alt", "start"},
+	)
+}
+
+// editorPolicies creates a policy allowing only the given tags, attributes and styles,
+// normalizing equivalent constructs. The editor policy is applied first, reading the original content.
+// Other tags are unwrapped, keeping their content, see contentPolicies.
+func editorPolicies(editor Policy, tags []atom.Atom, attrs []string, styles ...string) Policy {
+	var policies []Policy
+	if len(styles) > 0 {
+		policies = append(policies, AllowStyles(styles...))
+	}
+
+	return Policies{
+		editor,
+		RenameTags(editorRenames),
+		When(OnTags(atom.Div), TagPolicy(func(tag *Tag) {
+			if hasBlockContent(tag) {
+				tag.Unwrap()
+				return
+			}
+			tag.SetAtom(atom.P)
+		})),
+		contentPolicies(tags, attrs, policies...),
+	}
+}
+
+// hasBlockContent checks if the tag contains any block tags, like p or table.
+func hasBlockContent(tag *Tag) bool {
+	if tag.node == nil {
+		return false
+	}
+
+	for child := range tag.node.ChildNodes() {
+		if child.Type != html.ElementNode {
+			continue
+		}
+		if _, ok := blockAtoms[child.DataAtom]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// removeAttrPrefix removes all attributes with normalized keys starting with the prefix.
+func removeAttrPrefix(tag *Tag, prefix string) {
+	prefix = Normalize(prefix)
+
+	var keys []string
+	for _, attr := range tag.attributes {
+		if strings.HasPrefix(attr.Key(), prefix) {
+			keys = append(keys, attr.Key())
+		}
+	}
+
+	for _, key := range keys {
+		tag.RemoveAttr(key)
+	}
+}
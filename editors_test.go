@@ -0,0 +1,70 @@
+package sanitize_test
+
+import (
+	"testing"
+
+	"github.com/sonalys/sanitize"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_EditorPolicies(t *testing.T) {
+	t.Run("tinymce", func(t *testing.T) {
+		got := sanitizeWith(t,
+			`<div style="text-align:center;position:fixed" data-mce-style="x">Hello <b>bold</b><br data-mce-bogus="1"/></div>`+
+				`<div><p>nested</p></div>`+
+				`<span data-mce-bogus="all">caret</span>`+
+				`<a href="https://a.example" data-mce-href="https://a.example" class="mce-item x" onclick="a()">link</a>`+
+				`<script>alert(1)</script>`,
+			sanitize.TinyMCEPolicies(),
+		)
+		require.Equal(t, `<html><head></head><body>`+
+			`<p style="text-align:center">Hello <strong>bold</strong></p>`+
+			`<p>nested</p>`+
+			`<a href="https://a.example">link</a>`+
+			`</body></html>`, got)
+	})
+
+	t.Run("quill", func(t *testing.T) {
+		got := sanitizeWith(t,
+			`<p class="ql-align-center ql-indent-2 ql-direction-rtl">Hello <i>world</i></p>`+
+				`<ol><li><span class="ql-ui" contenteditable="false"></span>item</li></ol>`+
+				`<pre class="ql-syntax">code</pre>`,
+			sanitize.QuillPolicies(),
+		)
+		require.Equal(t, `<html><head></head><body>`+
+			`<p style="text-align:center;padding-left:6em" dir="rtl">Hello <em>world</em></p>`+
+			`<ol><li>item</li></ol>`+
+			`<pre>code</pre>`+
+			`</body></html>`, got)
+	})
+
+	t.Run("prosemirror", func(t *testing.T) {
+		got := sanitizeWith(t,
+			`<p data-pm-slice="1 1 []">Hello<img class="ProseMirror-separator"/><br class="ProseMirror-trailingBreak"/></p>`+
+				`<ol start="3"><li><p><b>bold</b><strike>old</strike></p></li></ol>`+
+				`<table><tr><td>no tables</td></tr></table>`,
+			sanitize.ProseMirrorPolicies(),
+		)
+		require.Equal(t, `<html><head></head><body>`+
+			`<p>Hello</p>`+
+			`<ol start="3"><li><p><strong>bold</strong>old</p></li></ol>`+
+			`no tables`+
+			`</body></html>`, got)
+	})
+
+	presets := []struct {
+		name   string
+		policy sanitize.Policy
+	}{
+		{name: "tinymce", policy: sanitize.TinyMCEPolicies()},
+		{name: "quill", policy: sanitize.QuillPolicies()},
+		{name: "prosemirror", policy: sanitize.ProseMirrorPolicies()},
+	}
+
+	for _, preset := range presets {
+		t.Run(preset.name+" should remove javascript urls", func(t *testing.T) {
+			got := sanitizeWith(t, `<a href="javascript:alert(1)">link</a><img src=" JavaScript:alert(1)">`, preset.policy)
+			require.Equal(t, `<html><head></head><body><a>link</a><img/></body></html>`, got)
+		})
+	}
+}
@@ -134,21 +134,21 @@ func BlacklistExternalSources() Policy {
 	})
 }
 
-// EnforceLinkNoRefNoFollow injects noref nofollow to all href attributes.
+// EnforceLinkNoRefNoFollow merges noreferrer and nofollow into the rel attribute of all links.
 // This enhances the privacy of the user when opening a given link.
+//
+// Links are a and area tags with href, and form tags with action, like in EnforceLinkRel.
+// Other tags with href, like link, are not changed, and existing rel tokens are kept.
+// Previously, the rel attribute of every tag with href was replaced by noreferrer nofollow.
 func EnforceLinkNoRefNoFollow() Policy {
-	return TagPolicy(func(tag *Tag) {
-		if !tag.HasAttr("href") {
-			return
-		}
-
-		tag.UpsertAttr("", "rel", "noreferrer nofollow")
-	})
+	return EnforceLinkRel("noreferrer", "nofollow")
 }
 
 // SecureEmailPolicy is a basic set of policies that:
 //   - Increases email privacy by blocking tracking attempts and external resources
 //   - Prevents basic XSS attempts on HTML attributes, scripts or iframes.
+//   - Denies dangerous tags and event handlers, so they cannot be allowed by extensions.
+//   - Denies color attributes with invalid colors.
 //
 // It does not sanitize CSS.
 // This policy can be extended with:
@@ -157,9 +157,12 @@ func EnforceLinkNoRefNoFollow() Policy {
 func DefaultEmailPolicies() Policy {
 	return Policies{
 		Blacklist(),
+		DenyDangerousTags(),
 		WhitelistEmailAttrs(),
 		WhitelistEmailTags(),
+		ValidateColors(),
 		BlacklistExternalSources(),
 		EnforceLinkNoRefNoFollow(),
+		BlockEventHandlers(),
 	}
 }
@@ -33,3 +33,21 @@ func Test_BlacklistExternalSources(t *testing.T) {
 		require.False(t, attr.IsBlocked())
 	})
 }
+
+func Test_EnforceLinkNoRefNoFollow(t *testing.T) {
+	t.Run("should merge rel tokens into links", func(t *testing.T) {
+		got := sanitizeWith(t,
+			`<a href="/a" rel="author">a</a><form action="/f"></form>`,
+			sanitize.EnforceLinkNoRefNoFollow(),
+		)
+		require.Equal(t, `<html><head></head><body><a href="/a" rel="author noreferrer nofollow">a</a><form action="/f" rel="noreferrer nofollow"></form></body></html>`, got)
+	})
+
+	t.Run("should not change link tags", func(t *testing.T) {
+		got := sanitizeWith(t,
+			`<link rel="stylesheet" href="a.css"/>`,
+			sanitize.EnforceLinkNoRefNoFollow(),
+		)
+		require.Equal(t, `<html><head><link rel="stylesheet" href="a.css"/></head><body></body></html>`, got)
+	})
+}
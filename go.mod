@@ -5,6 +5,7 @@ go 1.24.2
 require (
 	github.com/stretchr/testify v1.11.1
 	golang.org/x/net v0.43.0
+	golang.org/x/text v0.28.0
 )
 
 require (
@@ -0,0 +1,294 @@
+package sanitize
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+type (
+	// inlineStyles moves the rules of style tags into the style attributes of the matched elements.
+	inlineStyles struct {
+		properties map[string]struct{}
+	}
+
+	// inlineRule is a declaration from a style tag, matching a single selector.
+	inlineRule struct {
+		selector    *Selector
+		specificity [3]int
+		decl        styleDeclaration
+		important   bool
+		order       int
+	}
+)
+
+// InlineStyles writes the declarations of style tags into the style attribute of each element they match,
+// and removes the style tags. It runs after the document was sanitized, so only the sanitized rules
+// of style tags kept by the policies are inlined.
+//
+// Elements receiving declarations are evaluated again by all policies, with their original attributes,
+// and the resulting style attribute is only written if the policies keep it, like AllowStyles or
+// BlockUIRedressing would. Other changes from this evaluation are discarded, but handlers, like the
+// one from ReportHiddenContent, are called again for these elements.
+//
+// Declarations are applied following the CSS cascade: important declarations win, then inline declarations,
+// then the most specific selector, and then the last declared rule.
+//
+// Only declarations with safe values are inlined, see AllowStyles. When properties are given, only these
+// properties are inlined. Rules that cannot be inlined, like @media rules or selectors with pseudo-classes
+// or sibling combinators, are removed.
+//
+// Example:
+//
+//	sanitize.InlineStyles("color", "background-color", "font-size")
+func InlineStyles(properties ...string) Policy {
+	policy := inlineStyles{}
+
+	if len(properties) > 0 {
+		policy.properties = make(map[string]struct{}, len(properties))
+		for _, property := range properties {
+			policy.properties[strings.ToLower(strings.TrimSpace(property))] = struct{}{}
+		}
+	}
+
+	return policy
+}
+
+// Apply does nothing, styles are inlined after the document was sanitized.
+func (p inlineStyles) Apply(*Tag) {}
+
+func (p inlineStyles) applyDocument(root *html.Node, doc *document, policies Policies) {
+	var (
+		rules  []inlineRule
+		styles []*html.Node
+	)
+
+	for node := range root.Descendants() {
+		if node.Type != html.ElementNode || node.DataAtom != atom.Style {
+			continue
+		}
+
+		styles = append(styles, node)
+		for child := range node.ChildNodes() {
+			if child.Type == html.TextNode {
+				rules = p.appendRules(rules, child.Data)
+			}
+		}
+	}
+
+	for _, style := range styles {
+		style.Parent.RemoveChild(style)
+	}
+
+	if len(rules) > 0 {
+		inlineNode(root, nil, rules, doc, policies)
+	}
+}
+
+// appendRules parses the stylesheet, appending a rule for each selector and declaration that can be inlined.
+func (p inlineStyles) appendRules(rules []inlineRule, css string) []inlineRule {
+	for _, rule := range parseStylesheet(stripCSSComments(css)) {
+		prelude := strings.TrimSpace(rule.prelude)
+		if !rule.hasBlock || strings.HasPrefix(prelude, "@") {
+			continue
+		}
+
+		var decls []inlineRule
+		styles, _ := parseStyle(rule.block)
+		for _, decl := range styles {
+			var important bool
+			decl.value, important = cutImportant(decl.value)
+
+			if !p.allows(decl) {
+				continue
+			}
+
+			decls = append(decls, inlineRule{decl: decl, important: important})
+		}
+
+		for _, raw := range splitCSS(prelude, ',') {
+			selector, err := ParseSelector(strings.TrimSpace(raw))
+			if err != nil {
+				continue
+			}
+
+			specificity := selector.groups[0].specificity()
+			for _, decl := range decls {
+				decl.selector = selector
+				decl.specificity = specificity
+				decl.order = len(rules)
+				rules = append(rules, decl)
+			}
+		}
+	}
+
+	return rules
+}
+
+func (p inlineStyles) allows(decl styleDeclaration) bool {
+	if p.properties != nil {
+		if _, ok := p.properties[decl.property]; !ok {
+			return false
+		}
+	}
+
+	return isSafeStyleValue(decl.property) && isSafeStyleValue(decl.value) && isValidStyleColor(decl)
+}
+
+// inlineNode applies the matching rules to the node's element descendants.
+func inlineNode(node *html.Node, parent *Tag, rules []inlineRule, doc *document, policies Policies) {
+	for child := range node.ChildNodes() {
+		if child.Type != html.ElementNode {
+			inlineNode(child, parent, rules, doc, policies)
+			continue
+		}
+
+		// Content inserted by policies has no original attributes.
+		original, ok := doc.originals[child]
+		if !ok {
+			original = child.Attr
+		}
+
+		tag := &Tag{
+			atom:       child.DataAtom,
+			data:       child.Data,
+			attributes: fromAttrs(original),
+			parent:     parent,
+			node:       child,
+			original:   original,
+			doc:        doc,
+		}
+
+		var matched []inlineRule
+		for _, rule := range rules {
+			if rule.selector.Match(tag) {
+				matched = append(matched, rule)
+			}
+		}
+
+		if len(matched) > 0 {
+			applyInlineRules(child, tag, matched, len(rules), policies)
+		}
+
+		inlineNode(child, tag, rules, doc, policies)
+	}
+}
+
+// applyInlineRules cascades the matched rules with the element's original style attribute,
+// and evaluates the result with the policies, writing it into the element if it is kept.
+func applyInlineRules(node *html.Node, tag *Tag, matched []inlineRule, inlineOrder int, policies Policies) {
+	value, _ := tag.originalAttrValue("style")
+	inline, _ := parseStyle(value)
+	for _, decl := range inline {
+		var important bool
+		decl.value, important = cutImportant(decl.value)
+
+		// Inline declarations are more specific than any selector.
+		matched = append(matched, inlineRule{
+			decl:        decl,
+			important:   important,
+			specificity: [3]int{1 << 16},
+			order:       inlineOrder,
+		})
+		inlineOrder++
+	}
+
+	winners := make(map[string]inlineRule, len(matched))
+	for _, rule := range matched {
+		if cur, ok := winners[rule.decl.property]; !ok || compareInlineRules(rule, cur) >= 0 {
+			winners[rule.decl.property] = rule
+		}
+	}
+
+	sorted := slices.SortedFunc(maps.Values(winners), func(a, b inlineRule) int {
+		return cmp.Compare(a.order, b.order)
+	})
+
+	decls := make([]styleDeclaration, 0, len(sorted))
+	for _, rule := range sorted {
+		decls = append(decls, rule.decl)
+	}
+
+	// The policies evaluate a new tag, so they cannot change anything else in the sanitized element.
+	evaluated := &Tag{
+		atom:   tag.atom,
+		data:   tag.data,
+		parent: tag.parent,
+		node:   node,
+		doc:    tag.doc,
+		attributes: slices.DeleteFunc(fromAttrs(tag.original), func(attr *Attribute) bool {
+			return attr.Key() == "style"
+		}),
+		original: tag.original,
+	}
+	evaluated.attributes = append(evaluated.attributes, NewAttribute("", "style", renderStyle(decls)))
+	applyPolicies(evaluated, policies)
+
+	style, ok := evaluated.attrValue("style")
+	for i := range node.Attr {
+		if node.Attr[i].Namespace == "" && Normalize(node.Attr[i].Key) == "style" {
+			if !ok {
+				node.Attr = slices.Delete(node.Attr, i, i+1)
+				return
+			}
+			node.Attr[i].Val = style
+			return
+		}
+	}
+
+	if ok {
+		node.Attr = append(node.Attr, html.Attribute{Key: "style", Val: style})
+	}
+}
+
+// cutImportant removes the !important annotation from a declaration value.
+func cutImportant(value string) (string, bool) {
+	value = strings.TrimSpace(value)
+
+	n := len(value) - len("important")
+	if n < 0 || !strings.EqualFold(value[n:], "important") {
+		return value, false
+	}
+
+	rest, ok := strings.CutSuffix(strings.TrimSpace(value[:n]), "!")
+	if !ok {
+		return value, false
+	}
+
+	return strings.TrimSpace(rest), true
+}
+
+// compareInlineRules compares rules by importance, specificity and order.
+func compareInlineRules(a, b inlineRule) int {
+	if a.important != b.important {
+		if a.important {
+			return 1
+		}
+		return -1
+	}
+
+	if c := slices.Compare(a.specificity[:], b.specificity[:]); c != 0 {
+		return c
+	}
+
+	return cmp.Compare(a.order, b.order)
+}
+
+// specificity returns the number of id, class and attribute, and type selectors.
+func (c *complexSelector) specificity() [3]int {
+	var specificity [3]int
+
+	for _, compound := range c.compounds {
+		specificity[0] += len(compound.ids)
+		specificity[1] += len(compound.classes) + len(compound.attrs)
+		if compound.tag != "" {
+			specificity[2]++
+		}
+	}
+
+	return specificity
+}
@@ -0,0 +1,95 @@
+package sanitize_test
+
+import (
+	"testing"
+
+	"github.com/sonalys/sanitize"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/html/atom"
+)
+
+func Test_InlineStyles(t *testing.T) {
+	t.Run("should cascade rules by specificity and order", func(t *testing.T) {
+		got := sanitizeWith(t,
+			`<style>p{color:red;margin:0} .a{color:blue} p{color:green} #b{font-size:12px} td p{font-weight:bold}</style>`+
+				`<p class="a" id="b">one</p><p>two</p>`,
+			sanitize.InlineStyles(),
+		)
+		require.Equal(t, `<html><head></head><body>`+
+			`<p class="a" id="b" style="margin:0;color:blue;font-size:12px">one</p>`+
+			`<p style="margin:0;color:green">two</p>`+
+			`</body></html>`, got)
+	})
+
+	t.Run("should respect inline and important declarations", func(t *testing.T) {
+		got := sanitizeWith(t,
+			`<style>div p{color:red !important;font-size:10px} p{font-size:20px!IMPORTANT}</style>`+
+				`<div><p style="color:blue;font-size:14px;text-align:center">text</p></div>`,
+			sanitize.InlineStyles(),
+		)
+		require.Equal(t, `<html><head></head><body>`+
+			`<div><p style="color:red;font-size:20px;text-align:center">text</p></div>`+
+			`</body></html>`, got)
+	})
+
+	t.Run("should only inline safe and allowed declarations", func(t *testing.T) {
+		got := sanitizeWith(t,
+			`<style>p{color:red;background:url(https://a.example/a.png);background:image-set("https://a.example/a.png" 1x);width:expression(alert(1));color:notacolor;margin:0}`+
+				` p:hover{color:blue} @media (max-width:600px){p{color:green}} a + p{color:blue}</style>`+
+				`<p>text</p>`,
+			sanitize.InlineStyles("color", "background", "width"),
+		)
+		require.Equal(t, `<html><head></head><body><p style="color:red">text</p></body></html>`, got)
+	})
+
+	t.Run("should evaluate inlined styles with the policies", func(t *testing.T) {
+		got := sanitizeWith(t,
+			`<style>p{position:fixed;z-index:9999;top:0;color:red;font-size:12px}</style><p>text</p>`,
+			sanitize.InlineStyles(),
+			sanitize.AllowStyles("color", "position", "z-index", "top"),
+			sanitize.BlockUIRedressing(),
+		)
+		require.Equal(t, `<html><head></head><body><p style="color:red">text</p></body></html>`, got)
+	})
+
+	t.Run("should only inline style tags kept by the policies", func(t *testing.T) {
+		got := sanitizeWith(t,
+			`<style>p{color:red}</style><p>text</p>`,
+			sanitize.Blacklist(),
+			sanitize.AllowTags(atom.Html, atom.Head, atom.Body, atom.P),
+			sanitize.DenyTags(atom.Style),
+			sanitize.AllowStyles("color"),
+			sanitize.InlineStyles(),
+		)
+		require.Equal(t, `<html><head></head><body><p>text</p></body></html>`, got)
+	})
+
+	t.Run("should inline sanitized rules", func(t *testing.T) {
+		got := sanitizeWith(t,
+			`<style>p{color:red;position:fixed;top:0}</style><p id="a" style="margin:0">text</p>`,
+			sanitize.NamespaceIDs("user-"),
+			sanitize.BlockUIRedressing(),
+			sanitize.InlineStyles(),
+		)
+		require.Equal(t, `<html><head></head><body><p id="user-a" style="color:red;margin:0">text</p></body></html>`, got)
+	})
+
+	t.Run("should not add blocked style attributes", func(t *testing.T) {
+		got := sanitizeWith(t,
+			`<style>p{color:red}</style><p>text</p>`,
+			sanitize.DefaultEmailPolicies(),
+			sanitize.InlineStyles(),
+		)
+		require.Equal(t, `<html><head></head><body><p>text</p></body></html>`, got)
+	})
+
+	t.Run("should not keep styles of blocked attributes", func(t *testing.T) {
+		got := sanitizeWith(t,
+			`<style>.x{color:red} b{color:blue}</style><p class="x"><b>bold</b></p>`,
+			sanitize.Blacklist(),
+			sanitize.AllowTags(atom.Html, atom.Head, atom.Body, atom.Style, atom.P),
+			sanitize.InlineStyles(),
+		)
+		require.Equal(t, `<html><head></head><body><p></p></body></html>`, got)
+	})
+}
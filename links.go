@@ -0,0 +1,40 @@
+package sanitize
+
+import (
+	"golang.org/x/net/html/atom"
+)
+
+// isLink matches a and area tags with href, and form tags with action.
+var isLink = Any(
+	All(OnTags(atom.A, atom.Area), WithAttr(OnAttrs("href"))),
+	All(OnTags(atom.Form), WithAttr(OnAttrs("action"))),
+)
+
+// EnforceLinkRel merges the given tokens into the rel attribute of all links.
+// Links are a and area tags with href, and form tags with action.
+//
+// Existing allowed rel tokens are kept, blocked rel attributes are replaced.
+//
+// Example:
+//
+//	sanitize.EnforceLinkRel("noopener", "noreferrer", "nofollow", "ugc")
+func EnforceLinkRel(tokens ...string) Policy {
+	return When(isLink, TagPolicy(func(tag *Tag) {
+		tag.MergeAttrTokens("rel", tokens...)
+	}))
+}
+
+// EnforceLinkTarget sets the target attribute of all links.
+// Links are a and area tags with href, and form tags with action.
+//
+// Targeting _blank also merges noopener into the rel attribute,
+// so the opened page cannot access the original window.
+func EnforceLinkTarget(target string) Policy {
+	return When(isLink, TagPolicy(func(tag *Tag) {
+		tag.UpsertAttr("", "target", target)
+
+		if Normalize(target) == "_blank" {
+			tag.MergeAttrTokens("rel", "noopener")
+		}
+	}))
+}
@@ -0,0 +1,46 @@
+package sanitize_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/sonalys/sanitize"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_EnforceLinkRel(t *testing.T) {
+	t.Run("should merge existing tokens", func(t *testing.T) {
+		content := []byte(`<a href="#" rel="author NoFollow">a</a><area href="#"/><form action="/"></form><form></form><link href="style.css" rel="stylesheet"/>`)
+		out := bytes.NewBuffer(make([]byte, 0, 1024))
+		err := sanitize.HTML(bytes.NewReader(content), out,
+			sanitize.EnforceLinkRel("noreferrer", "nofollow"),
+		)
+		require.NoError(t, err)
+
+		require.Equal(t, `<html><head></head><body><a href="#" rel="author NoFollow noreferrer">a</a><area href="#" rel="noreferrer nofollow"/><form action="/" rel="noreferrer nofollow"></form><form></form><link href="style.css" rel="stylesheet"/></body></html>`, out.String())
+	})
+
+	t.Run("should replace blocked rel", func(t *testing.T) {
+		content := []byte(`<a href="#" rel="opener">a</a>`)
+		out := bytes.NewBuffer(make([]byte, 0, 1024))
+		err := sanitize.HTML(bytes.NewReader(content), out,
+			sanitize.BlockAttrs("rel"),
+			sanitize.EnforceLinkRel("nofollow"),
+		)
+		require.NoError(t, err)
+
+		require.Equal(t, `<html><head></head><body><a href="#" rel="nofollow">a</a></body></html>`, out.String())
+	})
+}
+
+func Test_EnforceLinkTarget(t *testing.T) {
+	content := []byte(`<a href="#" target="_self" rel="ugc">a</a><a>b</a>`)
+	out := bytes.NewBuffer(make([]byte, 0, 1024))
+	err := sanitize.HTML(bytes.NewReader(content), out,
+		sanitize.EnforceLinkTarget("_blank"),
+		sanitize.EnforceLinkNoRefNoFollow(),
+	)
+	require.NoError(t, err)
+
+	require.Equal(t, `<html><head></head><body><a href="#" target="_blank" rel="ugc noopener noreferrer nofollow">a</a><a>b</a></body></html>`, out.String())
+}
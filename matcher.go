@@ -0,0 +1,196 @@
+package sanitize
+
+import (
+	"strings"
+
+	"golang.org/x/net/html/atom"
+)
+
+type (
+	// matchable are the types that can be evaluated by a Matcher.
+	matchable interface {
+		*Tag | *Attribute
+	}
+
+	// Matcher is a predicate over tags or attributes.
+	// Matchers can be combined with Not, Any and All.
+	Matcher[T matchable] func(T) bool
+
+	// TagMatcher is a predicate over tags.
+	TagMatcher = Matcher[*Tag]
+
+	// AttrMatcher is a predicate over attributes.
+	AttrMatcher = Matcher[*Attribute]
+
+	// conditionalPolicy applies the policies to matching tags, and to the texts directly inside them.
+	conditionalPolicy struct {
+		matcher  TagMatcher
+		policies Policies
+	}
+)
+
+// Not inverts the result of the given matcher.
+func Not[T matchable](matcher Matcher[T]) Matcher[T] {
+	return func(value T) bool {
+		return !matcher(value)
+	}
+}
+
+// Any matches if at least one of the matchers match.
+// It never matches if no matchers are given.
+func Any[T matchable](matchers ...Matcher[T]) Matcher[T] {
+	return func(value T) bool {
+		for _, matcher := range matchers {
+			if matcher(value) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// All matches if all the matchers match.
+// It always matches if no matchers are given.
+func All[T matchable](matchers ...Matcher[T]) Matcher[T] {
+	return func(value T) bool {
+		for _, matcher := range matchers {
+			if !matcher(value) {
+				return false
+			}
+		}
+
+		return true
+	}
+}
+
+// When applies the policies only to tags that match the given matcher.
+// Text policies are applied to the texts and comments whose parent tag matches,
+// while texts outside tags are never matched.
+//
+// Policies transforming the whole document, like InlineStyles, are ignored, as they do not apply to tags.
+//
+// Example:
+//
+//	sanitize.When(sanitize.OnTags(atom.Img), sanitize.BlockAttrs("width"))
+func When(matcher TagMatcher, policies ...Policy) Policy {
+	return conditionalPolicy{matcher: matcher, policies: policies}
+}
+
+func (p conditionalPolicy) Apply(tag *Tag) {
+	if p.matcher(tag) {
+		p.policies.Apply(tag)
+	}
+}
+
+func (p conditionalPolicy) ApplyText(text *Text) {
+	if text.parent != nil && p.matcher(text.parent) {
+		p.policies.ApplyText(text)
+	}
+}
+
+// Unless applies the policies only to tags that don't match the given matcher.
+func Unless(matcher TagMatcher, policies ...Policy) Policy {
+	return When(Not(matcher), policies...)
+}
+
+// WhenAttr applies the handlers only to attributes that match the given matcher.
+func WhenAttr(matcher AttrMatcher, handlers ...AttributePolicy) AttributePolicy {
+	return func(attr *Attribute) {
+		if !matcher(attr) {
+			return
+		}
+
+		for _, handler := range handlers {
+			handler(attr)
+		}
+	}
+}
+
+// UnlessAttr applies the handlers only to attributes that don't match the given matcher.
+func UnlessAttr(matcher AttrMatcher, handlers ...AttributePolicy) AttributePolicy {
+	return WhenAttr(Not(matcher), handlers...)
+}
+
+// OnTags matches tags with any of the given atoms.
+func OnTags(atoms ...atom.Atom) TagMatcher {
+	set := make(map[atom.Atom]struct{}, len(atoms))
+
+	for _, atom := range atoms {
+		set[atom] = struct{}{}
+	}
+
+	return func(tag *Tag) bool {
+		_, ok := set[tag.atom]
+		return ok
+	}
+}
+
+// WithAttr matches tags containing at least one attribute matching the given matcher.
+//
+// Example:
+//
+//	sanitize.WithAttr(sanitize.OnAttrs("href"))
+func WithAttr(matcher AttrMatcher) TagMatcher {
+	return func(tag *Tag) bool {
+		for _, attr := range tag.attributes {
+			if matcher(attr) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// OnAttrs matches attributes with any of the given keys.
+// Keys are normalized before comparison.
+func OnAttrs(keys ...string) AttrMatcher {
+	set := make(map[string]struct{}, len(keys))
+
+	for _, key := range keys {
+		normalizedKey := Normalize(key)
+		set[normalizedKey] = struct{}{}
+	}
+
+	return func(attr *Attribute) bool {
+		_, ok := set[attr.Key()]
+		return ok
+	}
+}
+
+// AttrValues matches attributes with any of the given values.
+// Values are normalized before comparison.
+func AttrValues(values ...string) AttrMatcher {
+	set := make(map[string]struct{}, len(values))
+
+	for _, value := range values {
+		normalizedValue := Normalize(value)
+		set[normalizedValue] = struct{}{}
+	}
+
+	return func(attr *Attribute) bool {
+		_, ok := set[attr.Value()]
+		return ok
+	}
+}
+
+// AttrValuePrefix matches attributes with values starting with any of the given prefixes.
+// Prefixes are normalized before comparison.
+func AttrValuePrefix(prefixes ...string) AttrMatcher {
+	normalizedPrefixes := make([]string, 0, len(prefixes))
+
+	for _, prefix := range prefixes {
+		normalizedPrefixes = append(normalizedPrefixes, Normalize(prefix))
+	}
+
+	return func(attr *Attribute) bool {
+		for _, prefix := range normalizedPrefixes {
+			if strings.HasPrefix(attr.Value(), prefix) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
@@ -0,0 +1,109 @@
+package sanitize_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/sonalys/sanitize"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/html/atom"
+)
+
+func Test_Matchers(t *testing.T) {
+	newTag := func(key, value string) *sanitize.Tag {
+		tag := &sanitize.Tag{}
+		tag.UpsertAttr("", key, value)
+		return tag
+	}
+
+	t.Run("OnAttrs should normalize keys", func(t *testing.T) {
+		matcher := sanitize.OnAttrs("SRC")
+		require.True(t, matcher(sanitize.NewAttribute("", "Src", "")))
+		require.False(t, matcher(sanitize.NewAttribute("", "href", "")))
+	})
+
+	t.Run("AttrValuePrefix should normalize values", func(t *testing.T) {
+		matcher := sanitize.AttrValuePrefix("cid:")
+		require.True(t, matcher(sanitize.NewAttribute("", "src", " CID:id")))
+		require.False(t, matcher(sanitize.NewAttribute("", "src", "http://cid:id")))
+	})
+
+	t.Run("WithAttr should match any attribute", func(t *testing.T) {
+		matcher := sanitize.WithAttr(sanitize.OnAttrs("href"))
+		require.True(t, matcher(newTag("href", "")))
+		require.False(t, matcher(newTag("src", "")))
+	})
+
+	t.Run("Not", func(t *testing.T) {
+		matcher := sanitize.Not(sanitize.WithAttr(sanitize.OnAttrs("href")))
+		require.False(t, matcher(newTag("href", "")))
+		require.True(t, matcher(newTag("src", "")))
+	})
+
+	t.Run("Any", func(t *testing.T) {
+		matcher := sanitize.Any(sanitize.OnAttrs("href"), sanitize.OnAttrs("src"))
+		require.True(t, matcher(sanitize.NewAttribute("", "src", "")))
+		require.False(t, matcher(sanitize.NewAttribute("", "title", "")))
+		require.False(t, sanitize.Any[*sanitize.Tag]()(newTag("src", "")))
+	})
+
+	t.Run("All", func(t *testing.T) {
+		matcher := sanitize.All(sanitize.OnAttrs("src"), sanitize.AttrValues("a"))
+		require.True(t, matcher(sanitize.NewAttribute("", "src", "A")))
+		require.False(t, matcher(sanitize.NewAttribute("", "src", "b")))
+		require.True(t, sanitize.All[*sanitize.Tag]()(newTag("src", "")))
+	})
+}
+
+func Test_When(t *testing.T) {
+	content := []byte(`<html><head></head><body><img src="cid:a"/><img src="http://a"/><a href="http://a"></a></body></html>`)
+	out := bytes.NewBuffer(make([]byte, 0, 1024))
+	err := sanitize.HTML(bytes.NewReader(content), out,
+		sanitize.When(sanitize.OnTags(atom.Img),
+			sanitize.Unless(
+				sanitize.WithAttr(sanitize.All(sanitize.OnAttrs("src"), sanitize.AttrValuePrefix("cid:"))),
+				sanitize.TagPolicy((*sanitize.Tag).Block),
+			),
+		),
+	)
+	require.NoError(t, err)
+
+	require.Equal(t, `<html><head></head><body><img src="cid:a"/><a href="http://a"></a></body></html>`, out.String())
+}
+
+func Test_When_TextPolicies(t *testing.T) {
+	t.Run("should apply text policies inside matching tags", func(t *testing.T) {
+		got := sanitizeWith(t,
+			`<p>a<!--c--></p><div>b</div>`,
+			sanitize.When(sanitize.Any(sanitize.OnTags(atom.P)), sanitize.TextPolicy(func(text *sanitize.Text) {
+				text.SetData(strings.ToUpper(text.Data()))
+			})),
+			sanitize.Unless(sanitize.OnTags(atom.P), sanitize.TextPolicy(func(text *sanitize.Text) {
+				text.SetData(text.Data() + "!")
+			})),
+		)
+		require.Equal(t, `<html><head></head><body><p>A<!--C--></p><div>b!</div></body></html>`, got)
+	})
+
+	t.Run("should ignore document policies", func(t *testing.T) {
+		got := sanitizeWith(t,
+			`<style>p{color:red}</style><p>a</p>`,
+			sanitize.When(sanitize.OnTags(atom.P), sanitize.InlineStyles()),
+		)
+		require.Equal(t, `<html><head><style>p{color:red}</style></head><body><p>a</p></body></html>`, got)
+	})
+}
+
+func Test_WhenAttr(t *testing.T) {
+	tag := &sanitize.Tag{}
+	tag.UpsertAttr("", "src", "http://a")
+	tag.UpsertAttr("", "href", "http://a")
+
+	policy := sanitize.WhenAttr(sanitize.OnAttrs("src"), (*sanitize.Attribute).Block)
+	policy.Apply(tag)
+
+	attrs := tag.Attrs()
+	require.True(t, attrs[0].IsBlocked())
+	require.False(t, attrs[1].IsBlocked())
+}
@@ -0,0 +1,84 @@
+package sanitize
+
+import (
+	"slices"
+	"strings"
+)
+
+// officePrefixes are the namespace prefixes of elements generated by Microsoft Office,
+// like <o:p>, <w:sdt> or <v:shape>.
+var officePrefixes = []string{"o:", "w:", "m:", "v:", "x:", "st1:"}
+
+// CleanOfficeHTML cleans HTML generated by Microsoft Outlook and Word, keeping its visible content:
+//   - Office namespace elements, like <o:p> or <w:sdt>, are unwrapped.
+//   - VML elements, like <v:roundrect>, are unwrapped, keeping any text box content.
+//   - Office xml data islands, like <xml><o:OfficeDocumentSettings>, are denied.
+//   - Conditional comments are removed, along with content only visible to Office, like <!--[if mso]>.
+//     Content hidden only from Office, like <!--[if !mso]><!-->content<!--<![endif]-->, is kept.
+//   - mso-* properties are removed from style attributes.
+//   - xmlns attributes are removed.
+//
+// Unwrapped content is still sanitized by the other policies.
+func CleanOfficeHTML() Policy {
+	return Policies{
+		TagPolicy(func(tag *Tag) {
+			if isOfficeElement(tag.data) {
+				tag.Unwrap()
+			}
+
+			if strings.EqualFold(tag.data, "xml") {
+				tag.Deny()
+			}
+
+			for _, attr := range slices.Clone(tag.attributes) {
+				if key := attr.Key(); key == "xmlns" || strings.HasPrefix(key, "xmlns:") {
+					tag.RemoveAttr(key)
+				}
+			}
+		}),
+		WhenAttr(OnAttrs("style"), func(attr *Attribute) {
+			decls, escaped := parseStyle(attr.value)
+			kept := make([]styleDeclaration, 0, len(decls))
+
+			for _, decl := range decls {
+				if !strings.HasPrefix(decl.property, "mso-") {
+					kept = append(kept, decl)
+				}
+			}
+
+			if escaped || len(kept) != len(decls) {
+				attr.SetValue(renderStyle(kept))
+			}
+		}),
+		TextPolicy(func(text *Text) {
+			if text.IsComment() && isConditionalComment(text.data) {
+				text.Block()
+			}
+		}),
+	}
+}
+
+func isOfficeElement(data string) bool {
+	data = strings.ToLower(data)
+
+	for _, prefix := range officePrefixes {
+		if strings.HasPrefix(data, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isConditionalComment checks for the start and end markers of conditional comments:
+//
+//	<!--[if mso]> hidden <![endif]-->
+//	<!--[if !mso]><!--> revealed <!--<![endif]-->
+//	<![if !supportLists]> revealed <![endif]>
+func isConditionalComment(data string) bool {
+	data = strings.ToLower(strings.TrimSpace(data))
+
+	return strings.HasPrefix(data, "[if") ||
+		strings.HasPrefix(data, "[endif]") ||
+		strings.HasPrefix(data, "<![endif]")
+}
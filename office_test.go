@@ -0,0 +1,31 @@
+package sanitize_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/sonalys/sanitize"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CleanOfficeHTML(t *testing.T) {
+	content := []byte(`<html xmlns:o="urn:schemas-microsoft-com:office:office"><head><!--[if gte mso 9]><xml><o:OfficeDocumentSettings><o:AllowPNG/></o:OfficeDocumentSettings></xml><![endif]--><xml><o:shapedefaults/></xml></head>` +
+		`<body><p class="MsoNormal" style="mso-margin-top-alt:auto;color:red">Hello<o:p>&nbsp;</o:p></p>` +
+		`<!--[if mso]><v:roundrect href="http://a"><v:textbox>Office only</v:textbox></v:roundrect><![endif]-->` +
+		`<!--[if !mso]><!--><a href="http://a">Button</a><!--<![endif]-->` +
+		`<v:rect><v:textbox><p>Text box</p></v:textbox></v:rect>` +
+		`<p><![if !supportLists]>1.<![endif]>Item</p></body></html>`)
+	out := bytes.NewBuffer(make([]byte, 0, 1024))
+	err := sanitize.HTML(bytes.NewReader(content), out,
+		sanitize.BlockUnknownAtoms(),
+		sanitize.CleanOfficeHTML(),
+	)
+	require.NoError(t, err)
+
+	require.Equal(t, "<html><head></head><body>"+
+		"<p class=\"MsoNormal\" style=\"color:red\">Hello\u00a0</p>"+
+		"<a href=\"http://a\">Button</a>"+
+		"<p>Text box</p>"+
+		"<p>1.Item</p>"+
+		"</body></html>", out.String())
+}
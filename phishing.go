@@ -0,0 +1,209 @@
+package sanitize
+
+import (
+	"html"
+	"net"
+	"strings"
+
+	"golang.org/x/net/html/atom"
+)
+
+type (
+	// LinkIssue is a set of deceptive patterns found in a link.
+	LinkIssue uint
+
+	// LinkFinding describes a deceptive link.
+	LinkFinding struct {
+		// Href is the link's destination.
+		Href string
+		// Host is the normalized host of the link's destination.
+		Host string
+		// Text is the visible text of the link.
+		Text string
+		// TextHost is the normalized host shown in the visible text, if any.
+		TextHost string
+		// Issues are all the deceptive patterns found.
+		Issues LinkIssue
+	}
+
+	// LinkFindingHandler handles deceptive links found by DetectDeceptiveLinks.
+	// It can modify the link tag, or report the finding.
+	LinkFindingHandler func(tag *Tag, finding LinkFinding)
+)
+
+const (
+	// LinkTextMismatch is set when the visible text shows a different host than the destination.
+	LinkTextMismatch LinkIssue = 1 << iota
+	// LinkPunycodeHost is set when the destination uses an internationalized domain, which can imitate other domains.
+	LinkPunycodeHost
+	// LinkIPHost is set when the destination is an IP address instead of a domain.
+	LinkIPHost
+	// LinkMixedScriptHost is set when the destination mixes letters from different scripts, like pаypal.com.
+	LinkMixedScriptHost
+)
+
+var linkIssueNames = []string{
+	"text-mismatch",
+	"punycode-host",
+	"ip-host",
+	"mixed-script-host",
+}
+
+// String returns the space separated names of the issues, like "text-mismatch ip-host".
+func (i LinkIssue) String() string {
+	var names []string
+
+	for bit, name := range linkIssueNames {
+		if i&(1<<bit) != 0 {
+			names = append(names, name)
+		}
+	}
+
+	return strings.Join(names, " ")
+}
+
+// Has checks if all the given issues are set.
+func (i LinkIssue) Has(issue LinkIssue) bool {
+	return i&issue == issue
+}
+
+// DetectDeceptiveLinks inspects a and area tags with http or https destinations, and calls the handlers
+// for any link that:
+//   - Shows a URL or domain in its text, with a different host than the destination.
+//   - Points to an internationalized domain, which can imitate other domains with similar characters.
+//   - Points to an IP address.
+//   - Points to a host mixing letters from different scripts, like pаypal.com with a Cyrillic а.
+//
+// Subdomains are not considered a mismatch, so a link showing example.com and pointing to
+// login.example.com is not reported.
+//
+// Example:
+//
+//	sanitize.DetectDeceptiveLinks(sanitize.AnnotateDeceptiveLinks("data-warning"), sanitize.RevealLinkHost())
+func DetectDeceptiveLinks(handlers ...LinkFindingHandler) Policy {
+	return When(All(OnTags(atom.A, atom.Area), WithAttr(OnAttrs("href"))), TagPolicy(func(tag *Tag) {
+		finding, ok := inspectLink(tag)
+		if !ok {
+			return
+		}
+
+		for _, handler := range handlers {
+			handler(tag, finding)
+		}
+	}))
+}
+
+// AnnotateDeceptiveLinks sets the attribute with the given key to the found issues,
+// like data-warning="text-mismatch".
+func AnnotateDeceptiveLinks(key string) LinkFindingHandler {
+	return func(tag *Tag, finding LinkFinding) {
+		tag.UpsertAttr("", key, finding.Issues.String())
+	}
+}
+
+// RevealLinkHost appends the real destination host to the link's text, like "mybank.com [evil.example]".
+func RevealLinkHost() LinkFindingHandler {
+	return func(tag *Tag, finding LinkFinding) {
+		tag.AppendHTML(" [" + html.EscapeString(finding.Host) + "]")
+	}
+}
+
+func inspectLink(tag *Tag) (LinkFinding, bool) {
+	href, _ := tag.attrValue("href")
+
+	u, err := parseURL(href)
+	if err != nil {
+		return LinkFinding{}, false
+	}
+
+	// Hosts are read the way browsers do, like evil.example from https:evil.example.
+	u, ok := urlHost(u)
+	if !ok || u.Host == "" {
+		return LinkFinding{}, false
+	}
+
+	if scheme := strings.ToLower(u.Scheme); scheme != "http" && scheme != "https" {
+		return LinkFinding{}, false
+	}
+
+	finding := LinkFinding{
+		Href: href,
+		Host: normalizeHost(u.Hostname()),
+		Text: strings.TrimSpace(tag.Text()),
+	}
+
+	if isIPHost(u.Hostname()) {
+		finding.Issues |= LinkIPHost
+	}
+
+	if isPunycodeHost(finding.Host) {
+		finding.Issues |= LinkPunycodeHost
+	}
+
+	if IsMixedScript(unicodeHost(finding.Host)) {
+		finding.Issues |= LinkMixedScriptHost
+	}
+
+	if textHost, ok := hostFromText(finding.Text); ok {
+		finding.TextHost = textHost
+		if !isSameSite(textHost, finding.Host) {
+			finding.Issues |= LinkTextMismatch
+		}
+	}
+
+	return finding, finding.Issues != 0
+}
+
+// hostFromText extracts the host from a text that looks like a URL or a domain.
+func hostFromText(text string) (string, bool) {
+	if text == "" || strings.ContainsAny(text, " \t\n\r") {
+		return "", false
+	}
+
+	rawURL := text
+	if !strings.Contains(text, "://") {
+		rawURL = "http://" + text
+	}
+
+	u, err := parseURL(rawURL)
+	if err != nil || u.Hostname() == "" {
+		return "", false
+	}
+
+	if net.ParseIP(u.Hostname()) != nil {
+		return normalizeHost(u.Hostname()), true
+	}
+
+	// Domains need at least one dot, and a top level domain made of letters.
+	host := normalizeHost(u.Hostname())
+	i := strings.LastIndexByte(host, '.')
+	if i <= 0 || i == len(host)-1 {
+		return "", false
+	}
+
+	tld := host[i+1:]
+	if strings.HasPrefix(tld, "xn--") {
+		return host, true
+	}
+	for _, c := range tld {
+		if c < 'a' || c > 'z' {
+			return "", false
+		}
+	}
+
+	return host, true
+}
+
+// isSameSite checks if one host is equal or a subdomain of the other.
+func isSameSite(a, b string) bool {
+	return a == b || strings.HasSuffix(a, "."+b) || strings.HasSuffix(b, "."+a)
+}
+
+func isPunycodeHost(host string) bool {
+	for _, label := range strings.Split(host, ".") {
+		if strings.HasPrefix(label, "xn--") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+package sanitize_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/sonalys/sanitize"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DetectDeceptiveLinks(t *testing.T) {
+	tests := []struct {
+		name   string
+		link   string
+		issues sanitize.LinkIssue
+		found  bool
+	}{
+		{name: "text mismatch", link: `<a href="https://evil.example/login">https://<b>mybank.com</b></a>`, issues: sanitize.LinkTextMismatch, found: true},
+		{name: "domain text mismatch", link: `<a href="https://evil.example/login">MyBank.com</a>`, issues: sanitize.LinkTextMismatch, found: true},
+		{name: "missing slashes", link: `<a href="https:evil.example">https://mybank.com</a>`, issues: sanitize.LinkTextMismatch, found: true},
+		{name: "single slash", link: `<a href="https:/evil.example/login">mybank.com</a>`, issues: sanitize.LinkTextMismatch, found: true},
+		{name: "subdomain", link: `<a href="https://login.mybank.com/">mybank.com</a>`},
+		{name: "same host", link: `<a href="https://mybank.com/">https://www.mybank.com</a>`},
+		{name: "plain text", link: `<a href="https://evil.example/">Click here.</a>`},
+		{name: "version text", link: `<a href="https://evil.example/">v1.2</a>`},
+		{name: "ip host", link: `<a href="http://0x7f.1/">open</a>`, issues: sanitize.LinkIPHost, found: true},
+		{name: "punycode host", link: `<a href="https://pаypal.com/">paypal.com</a>`, issues: sanitize.LinkPunycodeHost | sanitize.LinkMixedScriptHost | sanitize.LinkTextMismatch, found: true},
+		{name: "single script idn", link: `<a href="https://пример.рф/">open</a>`, issues: sanitize.LinkPunycodeHost, found: true},
+		{name: "non http", link: `<a href="mailto:a@evil.example">mybank.com</a>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				found   bool
+				finding sanitize.LinkFinding
+			)
+
+			out := bytes.NewBuffer(make([]byte, 0, 1024))
+			err := sanitize.HTML(bytes.NewReader([]byte(tt.link)), out,
+				sanitize.DetectDeceptiveLinks(func(_ *sanitize.Tag, f sanitize.LinkFinding) {
+					found = true
+					finding = f
+				}),
+			)
+			require.NoError(t, err)
+
+			require.Equal(t, tt.found, found)
+			require.Equal(t, tt.issues, finding.Issues)
+		})
+	}
+
+	t.Run("annotate and reveal", func(t *testing.T) {
+		content := []byte(`<a href="https://evil.example/login">mybank.com</a>`)
+		out := bytes.NewBuffer(make([]byte, 0, 1024))
+		err := sanitize.HTML(bytes.NewReader(content), out,
+			sanitize.DetectDeceptiveLinks(
+				sanitize.AnnotateDeceptiveLinks("data-warning"),
+				sanitize.RevealLinkHost(),
+			),
+		)
+		require.NoError(t, err)
+
+		require.Equal(t, `<html><head></head><body><a href="https://evil.example/login" data-warning="text-mismatch">mybank.com [evil.example]</a></body></html>`, out.String())
+	})
+}
@@ -15,6 +15,11 @@ type (
 	Policies []Policy
 
 	TagPolicy func(*Tag)
+
+	// finalPolicy is a policy deferred until all regular policies were applied to a tag.
+	finalPolicy struct {
+		Policy
+	}
 )
 
 func (p TagPolicy) Apply(tag *Tag) {
@@ -27,6 +32,30 @@ func (p Policies) Apply(tag *Tag) {
 	}
 }
 
+func (p finalPolicy) Apply(tag *Tag) {
+	tag.finals = append(tag.finals, p.Policy)
+}
+
+// Final creates a policy that is only applied after all the other policies.
+// Final policies run in the order they were declared, and their decisions
+// cannot be overridden by regular policies, independently of the declaration order.
+func Final(policies ...Policy) Policy {
+	return finalPolicy{
+		Policy: Policies(policies),
+	}
+}
+
+// BlockEventHandlers will deny all event handler attributes, like onclick or onerror.
+// It's a final policy, so subsequent allow policies cannot re-enable event handlers.
+// Namespaced keys, like xlink:onclick, are also denied.
+func BlockEventHandlers() Policy {
+	return Final(AttributePolicy(func(attr *Attribute) {
+		if isEventHandler(attr.Key()) {
+			attr.Deny()
+		}
+	}))
+}
+
 // Blacklist blocks all tags and attributes by default.
 // Starting from a Blacklist is considered more safe as it will block new parts by default.
 func Blacklist() Policy {
@@ -52,12 +81,8 @@ func BlockUnknownAtoms() Policy {
 // It receives a [translator] func that receives the current value of the attribute.
 // Any returned value will be escaped for the attribute quoted representation.
 func TranslateSources(translator func(string) string) Policy {
-	return TagPolicy(func(tag *Tag) {
-		tag.AttrPolicy(func(attr *Attribute) {
-			if key := attr.Key(); key == "href" || key == "src" {
-				attr.SetValue(translator(attr.value))
-			}
-		})
+	return WhenAttr(OnAttrs("href", "src"), func(attr *Attribute) {
+		attr.SetValue(translator(attr.value))
 	})
 }
 
@@ -130,3 +155,63 @@ func BlockAttrs(keys ...string) Policy {
 		}
 	})
 }
+
+// DenyTags will mark tags as denied.
+// Unlike BlockTags, subsequent policies cannot allow denied tags.
+func DenyTags(atoms ...atom.Atom) Policy {
+	set := make(map[atom.Atom]struct{}, len(atoms))
+
+	for _, atom := range atoms {
+		set[atom] = struct{}{}
+	}
+
+	return TagPolicy(func(tag *Tag) {
+		if _, denied := set[tag.atom]; denied {
+			tag.Deny()
+		}
+	})
+}
+
+// DenyAttrs will mark attributes as denied.
+// Unlike BlockAttrs, subsequent policies cannot allow denied attributes.
+func DenyAttrs(keys ...string) Policy {
+	set := make(map[string]struct{}, len(keys))
+
+	for _, key := range keys {
+		normalizedKey := Normalize(key)
+		set[normalizedKey] = struct{}{}
+	}
+
+	return AttributePolicy(func(attr *Attribute) {
+		if _, denied := set[attr.Key()]; denied {
+			attr.Deny()
+		}
+	})
+}
+
+// DenyDangerousTags will deny tags that can execute code, embed external documents or
+// change how the document is interpreted: script, iframe, frame, frameset, object, embed,
+// applet, base and meta refresh.
+//
+// Denied tags cannot be allowed by subsequent policies.
+func DenyDangerousTags() Policy {
+	return Policies{
+		DenyTags(
+			atom.Script,
+			atom.Iframe,
+			atom.Frame,
+			atom.Frameset,
+			atom.Object,
+			atom.Embed,
+			atom.Applet,
+			atom.Base,
+		),
+		When(
+			All(
+				OnTags(atom.Meta),
+				WithAttr(All(OnAttrs("http-equiv"), AttrValues("refresh"))),
+			),
+			TagPolicy((*Tag).Deny),
+		),
+	}
+}
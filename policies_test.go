@@ -33,6 +33,15 @@ func Test_BlockAttrs(t *testing.T) {
 	require.Equal(t, "<html><head></head><body></body></html>", out.String())
 }
 
+func Test_AllowStyles(t *testing.T) {
+	tag := &sanitize.Tag{}
+	tag.UpsertAttr("", "style", `background-image:image-set("https://t.example/x.png" 1x);background:-webkit-image-set('https://t.example/x.png' 1x);color:red`)
+
+	sanitize.AllowStyles("background-image", "background", "color").Apply(tag)
+
+	require.Equal(t, "color:red", tag.Attrs()[0].Value())
+}
+
 func Test_BlockTags(t *testing.T) {
 	content := []byte(`<html><head></head><body><A/></body></html>`)
 	out := bytes.NewBuffer(make([]byte, 0, 1024))
@@ -55,3 +64,54 @@ func Test_AllowTags(t *testing.T) {
 
 	require.Equal(t, "<html><body><a></a></body></html>", out.String())
 }
+
+func Test_BlockEventHandlers(t *testing.T) {
+	content := []byte(`<html><head></head><body onload="alert(1)" ONERROR="alert(1)" xlink:onclick="alert(1)" title="ok"></body></html>`)
+	out := bytes.NewBuffer(make([]byte, 0, 1024))
+	err := sanitize.HTML(bytes.NewReader(content), out,
+		sanitize.BlockEventHandlers(),
+		sanitize.AllowAttrs("onload", "onerror", "xlink:onclick"),
+	)
+	require.NoError(t, err)
+
+	require.Equal(t, `<html><head></head><body title="ok"></body></html>`, out.String())
+}
+
+func Test_Final(t *testing.T) {
+	content := []byte(`<html><head></head><body><a></a></body></html>`)
+	out := bytes.NewBuffer(make([]byte, 0, 1024))
+	err := sanitize.HTML(bytes.NewReader(content), out,
+		sanitize.Final(sanitize.BlockTags(atom.A)),
+		sanitize.AllowTags(atom.A),
+	)
+	require.NoError(t, err)
+
+	require.Equal(t, "<html><head></head><body></body></html>", out.String())
+}
+
+func Test_DenyDangerousTags(t *testing.T) {
+	t.Run("should not be allowed by subsequent policies", func(t *testing.T) {
+		content := []byte(`<html><head><base href="http://evil"/><meta http-equiv="Refresh" content="0"/><meta charset="utf-8"/></head><body><script>alert(1)</script><iframe></iframe><object></object><embed/><a></a></body></html>`)
+		out := bytes.NewBuffer(make([]byte, 0, 1024))
+		err := sanitize.HTML(bytes.NewReader(content), out,
+			sanitize.DefaultEmailPolicies(),
+			sanitize.AllowTags(atom.Script, atom.Iframe, atom.Object, atom.Embed, atom.Base, atom.Meta),
+		)
+		require.NoError(t, err)
+
+		require.Equal(t, `<html><head><meta/></head><body><a></a></body></html>`, out.String())
+	})
+
+	t.Run("should keep denied attributes on upsert", func(t *testing.T) {
+		tag := &sanitize.Tag{}
+		tag.UpsertAttr("", "key", "value")
+
+		sanitize.DenyAttrs("key").Apply(tag)
+		tag.UpsertAttr("", "Key", "other")
+		sanitize.AllowAttrs("key").Apply(tag)
+
+		attr := tag.Attrs()[0]
+		require.True(t, attr.IsBlocked())
+		require.True(t, attr.IsDenied())
+	})
+}
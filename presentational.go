@@ -0,0 +1,223 @@
+package sanitize
+
+import (
+	"strings"
+
+	"golang.org/x/net/html/atom"
+)
+
+// ConvertPresentationalAttrs converts legacy presentational attributes, commonly found in emails,
+// into their equivalent CSS declarations in the style attribute:
+//
+//	bgcolor                  -> background-color
+//	text (body)              -> color
+//	color, face, size (font) -> color, font-family, font-size
+//	align                    -> text-align, float or margins for tables, float or vertical-align for images
+//	valign                   -> vertical-align
+//	width, height            -> width, height, for tables, cells, columns and rules
+//	border                   -> border, for tables and images, also applied to the table cells
+//	cellpadding              -> padding, applied to the table cells
+//	cellspacing              -> border-spacing
+//	nowrap                   -> white-space
+//
+// Converted attributes are denied, and invalid values are discarded. Values are validated by SetStyle,
+// and style sanitizers, like AllowStyles, should be declared after this policy.
+//
+// Like SetStyle, the style attribute is allowed even if previous policies blocked it, or would have
+// blocked a new one, like DefaultEmailPolicies. Declarations of a blocked style attribute are removed,
+// keeping only the converted ones.
+//
+// Example:
+//
+//	sanitize.Policies{
+//		sanitize.DefaultEmailPolicies(),
+//		sanitize.ConvertPresentationalAttrs(),
+//		sanitize.AllowStyles("color", "background-color", "text-align", "padding"),
+//	}
+func ConvertPresentationalAttrs() Policy {
+	return TagPolicy(func(tag *Tag) {
+		convert := func(key string, declarations func(value string) []styleDeclaration) {
+			value, ok := presentationalAttr(tag, key)
+			if !ok {
+				return
+			}
+
+			for _, decl := range declarations(strings.TrimSpace(value)) {
+				tag.SetStyle(decl.property, decl.value)
+			}
+
+			denyAttr(tag, key)
+		}
+
+		convert("bgcolor", declare("background-color", legacyColor))
+		convert("align", func(value string) []styleDeclaration {
+			return alignDeclarations(tag.atom, value)
+		})
+		convert("valign", declare("vertical-align", keyword("top", "middle", "bottom", "baseline")))
+
+		switch tag.atom {
+		case atom.Body:
+			convert("text", declare("color", legacyColor))
+		case atom.Font:
+			convert("color", declare("color", legacyColor))
+			convert("face", declare("font-family", strings.TrimSpace))
+			convert("size", declare("font-size", func(value string) string {
+				size, _ := fontSize(value)
+				return size
+			}))
+		case atom.Table:
+			convert("width", declare("width", legacyLength))
+			convert("height", declare("height", legacyLength))
+			convert("border", declare("border", borderWidth))
+			convert("cellspacing", declare("border-spacing", legacyLength))
+			// Cells read the table's padding and border when they are sanitized.
+			denyAttr(tag, "cellpadding")
+		case atom.Td, atom.Th:
+			convert("width", declare("width", legacyLength))
+			convert("height", declare("height", legacyLength))
+			convert("nowrap", declare("white-space", func(string) string { return "nowrap" }))
+
+			if padding, ok := tableAttr(tag, "cellpadding"); ok {
+				tag.SetStyle("padding", legacyLength(padding))
+			}
+			if border, ok := tableAttr(tag, "border"); ok {
+				if width := borderWidth(border); width != "" && width != "0" {
+					tag.SetStyle("border", "1px solid")
+				}
+			}
+		case atom.Col, atom.Colgroup, atom.Hr:
+			convert("width", declare("width", legacyLength))
+		case atom.Img:
+			convert("border", declare("border", borderWidth))
+		}
+	})
+}
+
+// presentationalAttr returns the raw value of the first attribute with the given normalized key.
+// Blocked attributes are considered, as their values can still be converted, but denied ones are not.
+func presentationalAttr(tag *Tag, key string) (string, bool) {
+	for _, attr := range tag.attributes {
+		if attr.Key() == key && !attr.IsDenied() {
+			return attr.value, true
+		}
+	}
+
+	return "", false
+}
+
+// tableAttr returns the raw value of an attribute from the closest table containing the cell.
+func tableAttr(tag *Tag, key string) (string, bool) {
+	for parent := tag.Parent(); parent != nil; parent = parent.Parent() {
+		if parent.atom != atom.Table {
+			continue
+		}
+
+		for _, attr := range parent.attributes {
+			if attr.Key() == key {
+				return attr.value, true
+			}
+		}
+
+		return "", false
+	}
+
+	return "", false
+}
+
+func denyAttr(tag *Tag, key string) {
+	for _, attr := range tag.attributes {
+		if attr.Key() == key {
+			attr.Deny()
+		}
+	}
+}
+
+// declare creates a single declaration for the property, discarding empty converted values.
+func declare(property string, converter func(value string) string) func(value string) []styleDeclaration {
+	return func(value string) []styleDeclaration {
+		if value = converter(value); value == "" {
+			return nil
+		}
+		return []styleDeclaration{{property: property, value: value}}
+	}
+}
+
+// keyword only accepts one of the given keywords, case-insensitive.
+func keyword(keywords ...string) func(value string) string {
+	return func(value string) string {
+		value = strings.ToLower(value)
+		for _, keyword := range keywords {
+			if value == keyword {
+				return value
+			}
+		}
+		return ""
+	}
+}
+
+func alignDeclarations(tag atom.Atom, value string) []styleDeclaration {
+	value = strings.ToLower(value)
+
+	switch tag {
+	case atom.Table:
+		switch value {
+		case "left", "right":
+			return []styleDeclaration{{property: "float", value: value}}
+		case "center":
+			return []styleDeclaration{{property: "margin-left", value: "auto"}, {property: "margin-right", value: "auto"}}
+		}
+	case atom.Img:
+		switch value {
+		case "left", "right":
+			return []styleDeclaration{{property: "float", value: value}}
+		case "top", "middle", "bottom", "baseline":
+			return []styleDeclaration{{property: "vertical-align", value: value}}
+		case "absmiddle":
+			return []styleDeclaration{{property: "vertical-align", value: "middle"}}
+		}
+	default:
+		switch value {
+		case "left", "right", "center", "justify":
+			return []styleDeclaration{{property: "text-align", value: value}}
+		}
+	}
+
+	return nil
+}
+
+// legacyColor validates colors from presentational attributes, returning empty for invalid colors.
+func legacyColor(value string) string {
+	color, _ := parseLegacyColor(value)
+	return color
+}
+
+// legacyLength converts pixel and percentage lengths, like 10 or 50%, into CSS lengths.
+// A trailing dot, like 1., is dropped, as CSS numbers cannot end with one.
+func legacyLength(value string) string {
+	number, percent := strings.CutSuffix(strings.TrimSpace(value), "%")
+	number = strings.TrimSuffix(number, ".")
+	if number == "" || strings.Trim(number, "0123456789.") != "" || strings.Count(number, ".") > 1 {
+		return ""
+	}
+
+	switch {
+	case percent:
+		return number + "%"
+	case strings.Trim(number, "0.") == "":
+		return "0"
+	default:
+		return number + "px"
+	}
+}
+
+// borderWidth converts a border width into a solid CSS border.
+func borderWidth(value string) string {
+	switch width := legacyLength(value); width {
+	case "":
+		return ""
+	case "0":
+		return "0"
+	default:
+		return width + " solid"
+	}
+}
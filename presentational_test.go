@@ -0,0 +1,86 @@
+package sanitize_test
+
+import (
+	"testing"
+
+	"github.com/sonalys/sanitize"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/html/atom"
+)
+
+func Test_ConvertPresentationalAttrs(t *testing.T) {
+	t.Run("should convert table attributes", func(t *testing.T) {
+		got := sanitizeWith(t,
+			`<table align="center" width="600" bgcolor="ffffff" cellpadding="4" cellspacing="0" border="1">`+
+				`<tr valign="top"><td align="right" width="50%" nowrap>cell</td></tr></table>`,
+			sanitize.ConvertPresentationalAttrs(),
+		)
+		require.Equal(t, `<html><head></head><body>`+
+			`<table style="background-color:#ffffff;margin-left:auto;margin-right:auto;width:600px;border:1px solid;border-spacing:0">`+
+			`<tbody><tr style="vertical-align:top"><td style="text-align:right;width:50%;white-space:nowrap;padding:4px;border:1px solid">cell</td></tr></tbody></table>`+
+			`</body></html>`, got)
+	})
+
+	t.Run("should convert font attributes", func(t *testing.T) {
+		got := sanitizeWith(t,
+			`<body text="#333"><font color="red" face="Arial, sans-serif" size="+1" style="font-weight:bold">text</font></body>`,
+			sanitize.ConvertPresentationalAttrs(),
+		)
+		require.Equal(t, `<html><head></head><body style="color:#333">`+
+			`<font style="font-weight:bold;color:red;font-family:Arial, sans-serif;font-size:large">text</font>`+
+			`</body></html>`, got)
+	})
+
+	t.Run("should convert image alignment and border", func(t *testing.T) {
+		got := sanitizeWith(t,
+			`<img src="a.png" align="left" border="0" width="10"><img src="b.png" align="absmiddle">`,
+			sanitize.ConvertPresentationalAttrs(),
+		)
+		require.Equal(t, `<html><head></head><body>`+
+			`<img src="a.png" width="10" style="float:left;border:0"/><img src="b.png" style="vertical-align:middle"/>`+
+			`</body></html>`, got)
+	})
+
+	t.Run("should drop trailing dots from lengths", func(t *testing.T) {
+		got := sanitizeWith(t,
+			`<table width="1." cellpadding="50.%" border="."><tr><td>cell</td></tr></table>`,
+			sanitize.ConvertPresentationalAttrs(),
+		)
+		require.Equal(t, `<html><head></head><body>`+
+			`<table style="width:1px"><tbody><tr><td style="padding:50%">cell</td></tr></tbody></table>`+
+			`</body></html>`, got)
+	})
+
+	t.Run("should discard invalid values", func(t *testing.T) {
+		got := sanitizeWith(t,
+			`<p align="evil" bgcolor="red;position:fixed">text</p><table width="1e9" cellpadding="x"><tr><td>cell</td></tr></table>`,
+			sanitize.ConvertPresentationalAttrs(),
+		)
+		require.Equal(t, `<html><head></head><body>`+
+			`<p>text</p><table><tbody><tr><td>cell</td></tr></tbody></table>`+
+			`</body></html>`, got)
+	})
+
+	t.Run("should allow converted styles with or without a blocked style", func(t *testing.T) {
+		got := sanitizeWith(t,
+			`<p bgcolor="red">new</p><p bgcolor="red" style="position:fixed;color:blue">existing</p>`,
+			sanitize.DefaultEmailPolicies(),
+			sanitize.ConvertPresentationalAttrs(),
+		)
+		require.Equal(t, `<html><head></head><body>`+
+			`<p style="background-color:red">new</p><p style="background-color:red">existing</p>`+
+			`</body></html>`, got)
+	})
+
+	t.Run("should convert blocked attributes", func(t *testing.T) {
+		got := sanitizeWith(t,
+			`<p align="center" bgcolor="red">text</p>`,
+			sanitize.Blacklist(),
+			sanitize.AllowTags(atom.Html, atom.Head, atom.Body, atom.P),
+			sanitize.ConvertPresentationalAttrs(),
+			sanitize.AllowAttrs("align", "bgcolor"),
+			sanitize.AllowStyles("text-align"),
+		)
+		require.Equal(t, `<html><head></head><body><p style="text-align:center">text</p></body></html>`, got)
+	})
+}
@@ -0,0 +1,123 @@
+package sanitize
+
+import (
+	"golang.org/x/net/html/atom"
+)
+
+// nonContentAtoms are the tags whose content is not readable text, removed by the content presets.
+var nonContentAtoms = []atom.Atom{
+	atom.Style,
+	atom.Title,
+	atom.Template,
+	atom.Noscript,
+	atom.Textarea,
+	atom.Select,
+}
+
+// ugcAtoms are the tags allowed in user generated content, like comments or forum posts.
+var ugcAtoms = []atom.Atom{
+	atom.P, atom.Br, atom.A, atom.B, atom.Strong, atom.I, atom.Em, atom.U, atom.S, atom.Del,
+	atom.Sub, atom.Sup, atom.Blockquote, atom.Q, atom.Code, atom.Pre, atom.Ul, atom.Ol, atom.Li,
+	atom.Hr, atom.Img,
+}
+
+// StrictTextPolicies removes all markup, keeping only the text of the document.
+//
+//	Tags:       none, tags are unwrapped into their text
+//	Attributes: none
+//
+// Comments and tags without readable text, like script, style, title or textarea, are removed with
+// their content.
+func StrictTextPolicies() Policy {
+	return contentPolicies(nil, nil)
+}
+
+// BasicFormattingPolicies allows basic inline formatting and links:
+//
+//	Tags:       p, br, a, b, strong, i, em, u
+//	Attributes: href, title
+//	Schemes:    http, https, mailto
+//
+// Links are marked with rel="nofollow". Other tags are unwrapped into their content.
+func BasicFormattingPolicies() Policy {
+	return contentPolicies(
+		[]atom.Atom{atom.P, atom.Br, atom.A, atom.B, atom.Strong, atom.I, atom.Em, atom.U},
+		[]string{"href", "title"},
+		EnforceLinkRel("nofollow"),
+	)
+}
+
+// UGCPolicies allows the formatting commonly used in comments and forum posts:
+//
+//	Tags:       p, br, a, b, strong, i, em, u, s, del, sub, sup, blockquote, q, code, pre,
+//	            ul, ol, li, hr, img
+//	Attributes: href, title, cite, src, alt, width, height
+//	Schemes:    http, https, mailto
+//
+// Links are marked with rel="nofollow ugc", so search engines don't endorse them.
+// Other tags are unwrapped into their content.
+func UGCPolicies() Policy {
+	return contentPolicies(
+		ugcAtoms,
+		[]string{"href", "title", "cite", "src", "alt", "width", "height"},
+		EnforceLinkRel("nofollow", "ugc"),
+	)
+}
+
+// ArticlePolicies allows the structure of long form articles, like blog posts or documentation:
+//
+//	Tags:       everything allowed by UGCPolicies, h1-h6, figure, figcaption, picture, source,
+//	            table, caption, colgroup, col, thead, tbody, tfoot, tr, th, td, dl, dt, dd,
+//	            abbr, mark, small, time, details, summary, section, article, aside, header, footer
+//	Attributes: everything allowed by UGCPolicies, srcset, sizes, colspan, rowspan, scope,
+//	            datetime, start, reversed, lang, dir, open, target
+//	Schemes:    http, https, mailto
+//
+// Links are marked with rel="noopener", so opened pages cannot access the original window.
+// Other tags are unwrapped into their content.
+func ArticlePolicies() Policy {
+	return contentPolicies(
+		append([]atom.Atom{
+			atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6, atom.Figure, atom.Figcaption,
+			atom.Picture, atom.Source, atom.Table, atom.Caption, atom.Colgroup, atom.Col, atom.Thead,
+			atom.Tbody, atom.Tfoot, atom.Tr, atom.Th, atom.Td, atom.Dl, atom.Dt, atom.Dd, atom.Abbr,
+			atom.Mark, atom.Small, atom.Time, atom.Details, atom.Summary, atom.Section, atom.Article,
+			atom.Aside, atom.Header, atom.Footer,
+		}, ugcAtoms...),
+		[]string{
+			"href", "title", "cite", "src", "alt", "width", "height", "srcset", "sizes", "colspan",
+			"rowspan", "scope", "datetime", "start", "reversed", "lang", "dir", "open", "target",
+		},
+		EnforceLinkRel("noopener"),
+	)
+}
+
+// contentPolicies creates a preset allowing only the given tags and attributes.
+// Other tags are unwrapped, keeping their content, while dangerous and non-content tags are removed.
+func contentPolicies(tags []atom.Atom, attrs []string, policies ...Policy) Policy {
+	allowed := make(map[atom.Atom]struct{}, len(tags)+3)
+	for _, tag := range append(tags, atom.Html, atom.Head, atom.Body) {
+		allowed[tag] = struct{}{}
+	}
+
+	return append(Policies{
+		Blacklist(),
+		DenyDangerousTags(),
+		DenyTags(nonContentAtoms...),
+		TagPolicy(func(tag *Tag) {
+			if _, ok := allowed[tag.atom]; ok {
+				tag.Allow()
+				return
+			}
+			tag.Unwrap()
+		}),
+		AllowAttrs(attrs...),
+		AllowURLSchemes("http", "https", "mailto"),
+		BlockEventHandlers(),
+		TextPolicy(func(text *Text) {
+			if text.IsComment() {
+				text.Block()
+			}
+		}),
+	}, policies...)
+}
@@ -0,0 +1,53 @@
+package sanitize_test
+
+import (
+	"testing"
+
+	"github.com/sonalys/sanitize"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Presets(t *testing.T) {
+	const content = `<h1 id="title">Title</h1>` +
+		`<p class="x">Hello <b>bold</b> <span>span</span><!-- comment --></p>` +
+		`<a href="https://a.example" target="_blank" onclick="a()">link</a>` +
+		`<a href="javascript:alert(1)">xss</a>` +
+		`<img src="https://a.example/a.png" alt="a">` +
+		`<table><tr><td colspan="2">cell</td></tr></table>` +
+		`<style>p{}</style><script>alert(1)</script><textarea>input</textarea>`
+
+	t.Run("strict text", func(t *testing.T) {
+		got := sanitizeWith(t, content, sanitize.StrictTextPolicies())
+		require.Equal(t, `<html><head></head><body>TitleHello bold spanlinkxsscell</body></html>`, got)
+	})
+
+	t.Run("basic formatting", func(t *testing.T) {
+		got := sanitizeWith(t, content, sanitize.BasicFormattingPolicies())
+		require.Equal(t, `<html><head></head><body>Title`+
+			`<p>Hello <b>bold</b> span</p>`+
+			`<a href="https://a.example" rel="nofollow">link</a>`+
+			`<a rel="nofollow">xss</a>`+
+			`cell</body></html>`, got)
+	})
+
+	t.Run("ugc", func(t *testing.T) {
+		got := sanitizeWith(t, content, sanitize.UGCPolicies())
+		require.Equal(t, `<html><head></head><body>Title`+
+			`<p>Hello <b>bold</b> span</p>`+
+			`<a href="https://a.example" rel="nofollow ugc">link</a>`+
+			`<a rel="nofollow ugc">xss</a>`+
+			`<img src="https://a.example/a.png" alt="a"/>`+
+			`cell</body></html>`, got)
+	})
+
+	t.Run("article", func(t *testing.T) {
+		got := sanitizeWith(t, content, sanitize.ArticlePolicies())
+		require.Equal(t, `<html><head></head><body><h1>Title</h1>`+
+			`<p>Hello <b>bold</b> span</p>`+
+			`<a href="https://a.example" target="_blank" rel="noopener">link</a>`+
+			`<a rel="noopener">xss</a>`+
+			`<img src="https://a.example/a.png" alt="a"/>`+
+			`<table><tbody><tr><td colspan="2">cell</td></tr></tbody></table>`+
+			`</body></html>`, got)
+	})
+}
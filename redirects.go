@@ -0,0 +1,183 @@
+package sanitize
+
+import (
+	"encoding/base64"
+	"net/url"
+	"strings"
+)
+
+// RedirectDecoder decodes the destination of a redirect wrapper URL, like Outlook Safe Links.
+// It returns false if the URL is not a wrapper handled by the decoder.
+type RedirectDecoder func(u *url.URL) (string, bool)
+
+// maxRedirectUnwraps limits how many nested wrappers are unwrapped from a single URL.
+const maxRedirectUnwraps = 5
+
+// proofpointRunLengths maps the characters used by Proofpoint v3 to encode run lengths.
+const proofpointRunLengths = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_"
+
+// DefaultRedirectDecoders returns the built-in decoders used by UnwrapRedirects.
+// It can be extended with custom decoders:
+//
+//	sanitize.UnwrapRedirects(append(sanitize.DefaultRedirectDecoders(), myDecoder)...)
+func DefaultRedirectDecoders() []RedirectDecoder {
+	return []RedirectDecoder{
+		OutlookSafeLinks(),
+		GoogleRedirect(),
+		ProofpointURLDefense(),
+	}
+}
+
+// UnwrapRedirects rewrites href attributes wrapped by redirect services to their real destination,
+// so subsequent policies and the user see the true URL. Decoding is done offline.
+// Nested wrappers are also unwrapped.
+//
+// Only http and https destinations are accepted, other wrapped URLs are kept as is.
+// If no decoders are given, DefaultRedirectDecoders are used.
+func UnwrapRedirects(decoders ...RedirectDecoder) Policy {
+	if len(decoders) == 0 {
+		decoders = DefaultRedirectDecoders()
+	}
+
+	return WhenAttr(OnAttrs("href"), func(attr *Attribute) {
+		rawURL := strings.TrimSpace(attr.value)
+		unwrapped := rawURL
+
+		for range maxRedirectUnwraps {
+			next, ok := unwrapRedirect(unwrapped, decoders)
+			if !ok {
+				break
+			}
+			unwrapped = next
+		}
+
+		if unwrapped != rawURL {
+			attr.SetValue(unwrapped)
+		}
+	})
+}
+
+func unwrapRedirect(rawURL string, decoders []RedirectDecoder) (string, bool) {
+	u, err := parseURL(rawURL)
+	if err != nil {
+		return "", false
+	}
+
+	for _, decoder := range decoders {
+		target, ok := decoder(u)
+		if !ok {
+			continue
+		}
+
+		targetURL, err := parseURL(target)
+		if err != nil || targetURL.Host == "" {
+			return "", false
+		}
+
+		switch strings.ToLower(targetURL.Scheme) {
+		case "http", "https":
+			return target, true
+		default:
+			return "", false
+		}
+	}
+
+	return "", false
+}
+
+// OutlookSafeLinks decodes Microsoft Defender Safe Links, like
+// https://nam02.safelinks.protection.outlook.com/?url=https%3A%2F%2Fexample.com&data=...
+func OutlookSafeLinks() RedirectDecoder {
+	return func(u *url.URL) (string, bool) {
+		if !strings.HasSuffix(normalizeHost(u.Hostname()), ".safelinks.protection.outlook.com") {
+			return "", false
+		}
+
+		target := u.Query().Get("url")
+		return target, target != ""
+	}
+}
+
+// GoogleRedirect decodes Google redirects, like https://www.google.com/url?q=https://example.com.
+func GoogleRedirect() RedirectDecoder {
+	return func(u *url.URL) (string, bool) {
+		host := normalizeHost(u.Hostname())
+		if host != "google.com" && host != "www.google.com" || u.Path != "/url" {
+			return "", false
+		}
+
+		query := u.Query()
+		target := query.Get("q")
+		if target == "" {
+			target = query.Get("url")
+		}
+
+		return target, target != ""
+	}
+}
+
+// ProofpointURLDefense decodes Proofpoint URL Defense links, versions 1, 2 and 3.
+func ProofpointURLDefense() RedirectDecoder {
+	return func(u *url.URL) (string, bool) {
+		switch host := normalizeHost(u.Hostname()); {
+		case host == "urldefense.proofpoint.com" && u.Path == "/v1/url":
+			target := u.Query().Get("u")
+			return target, target != ""
+		case host == "urldefense.proofpoint.com" && u.Path == "/v2/url":
+			encoded := strings.NewReplacer("-", "%", "_", "/").Replace(u.Query().Get("u"))
+			target, err := url.PathUnescape(encoded)
+			return target, err == nil && target != ""
+		case host == "urldefense.com" && strings.HasPrefix(u.EscapedPath(), "/v3/__"):
+			return decodeProofpointV3(u.String())
+		default:
+			return "", false
+		}
+	}
+}
+
+// decodeProofpointV3 decodes links like https://urldefense.com/v3/__https://example.com/*__;Iw!!ID!token$.
+// Characters replaced by * are stored base64 encoded right after the embedded URL.
+func decodeProofpointV3(rawURL string) (string, bool) {
+	_, rest, _ := strings.Cut(rawURL, "/v3/__")
+
+	embedded, rest, ok := strings.Cut(rest, "__;")
+	if !ok {
+		return "", false
+	}
+
+	encodedChars, _, _ := strings.Cut(rest, "!")
+	decoded, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(encodedChars, "="))
+	if err != nil {
+		return "", false
+	}
+	chars := []rune(string(decoded))
+
+	var (
+		b    strings.Builder
+		next int
+	)
+
+	for i := 0; i < len(embedded); i++ {
+		if embedded[i] != '*' {
+			b.WriteByte(embedded[i])
+			continue
+		}
+
+		length := 1
+		if i+2 < len(embedded) && embedded[i+1] == '*' {
+			length = strings.IndexByte(proofpointRunLengths, embedded[i+2]) + 2
+			if length < 2 {
+				return "", false
+			}
+			i += 2
+		}
+
+		if next+length > len(chars) {
+			return "", false
+		}
+		b.WriteString(string(chars[next : next+length]))
+		next += length
+	}
+
+	return b.String(), true
+}
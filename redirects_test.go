@@ -0,0 +1,93 @@
+package sanitize_test
+
+import (
+	"bytes"
+	"html"
+	"net/url"
+	"testing"
+
+	"github.com/sonalys/sanitize"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_UnwrapRedirects(t *testing.T) {
+	tests := []struct {
+		name     string
+		href     string
+		expected string
+	}{
+		{
+			name:     "outlook safe links",
+			href:     "https://nam02.safelinks.protection.outlook.com/?url=https%3A%2F%2Fexample.com%2Fpath%3Fa%3D1&amp;data=abc&amp;reserved=0",
+			expected: "https://example.com/path?a=1",
+		},
+		{
+			name:     "google redirect",
+			href:     "https://www.google.com/url?q=https://example.com/&amp;sa=D",
+			expected: "https://example.com/",
+		},
+		{
+			name:     "proofpoint v2",
+			href:     "https://urldefense.proofpoint.com/v2/url?u=https-3A__media.example.com_images_jupiter-2Dnasa.jpg.638x0-5Fq80.jpg&amp;d=DwMFaQ",
+			expected: "https://media.example.com/images/jupiter-nasa.jpg.638x0_q80.jpg",
+		},
+		{
+			name:     "proofpoint v3",
+			href:     "https://urldefense.com/v3/__https://google.com:443/search?q=a*test&amp;gs=ps__;Kw!-612Flbf0JvQ3kNJkRi5Jg!Ue6tQudNKaShHg93trcdjqDP8se2ySE65jyCIe2K1D_uNjZ1Lnf6YLQERujngZv9UWf66ujQIQ$",
+			expected: "https://google.com:443/search?q=a+test&gs=ps",
+		},
+		{
+			name:     "nested",
+			href:     "https://www.google.com/url?q=https%3A%2F%2Fnam02.safelinks.protection.outlook.com%2F%3Furl%3Dhttps%253A%252F%252Fexample.com",
+			expected: "https://example.com",
+		},
+		{
+			name:     "unsafe destination",
+			href:     "https://www.google.com/url?q=javascript:alert(1)",
+			expected: "https://www.google.com/url?q=javascript:alert(1)",
+		},
+		{
+			name:     "not wrapped",
+			href:     "https://example.com/url?q=https://other.example",
+			expected: "https://example.com/url?q=https://other.example",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sources []string
+
+			content := []byte(`<a href="` + tt.href + `">a</a>`)
+			out := bytes.NewBuffer(make([]byte, 0, 1024))
+			err := sanitize.HTML(bytes.NewReader(content), out,
+				sanitize.UnwrapRedirects(),
+				sanitize.TranslateSources(func(s string) string {
+					sources = append(sources, s)
+					return s
+				}),
+			)
+			require.NoError(t, err)
+
+			require.Equal(t, []string{tt.expected}, sources)
+			require.Equal(t, `<html><head></head><body><a href="`+html.EscapeString(tt.expected)+`">a</a></body></html>`, out.String())
+		})
+	}
+
+	t.Run("custom decoder", func(t *testing.T) {
+		decoder := func(u *url.URL) (string, bool) {
+			if u.Host != "track.example" {
+				return "", false
+			}
+			return u.Query().Get("to"), true
+		}
+
+		content := []byte(`<a href="https://track.example/?to=https://example.com">a</a>`)
+		out := bytes.NewBuffer(make([]byte, 0, 1024))
+		err := sanitize.HTML(bytes.NewReader(content), out,
+			sanitize.UnwrapRedirects(append(sanitize.DefaultRedirectDecoders(), decoder)...),
+		)
+		require.NoError(t, err)
+
+		require.Equal(t, `<html><head></head><body><a href="https://example.com">a</a></body></html>`, out.String())
+	})
+}
@@ -0,0 +1,326 @@
+package sanitize
+
+import (
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html/atom"
+)
+
+type (
+	// RedressOption configures BlockUIRedressing.
+	RedressOption func(*redressConfig)
+
+	// redressPolicy removes redressing declarations from style attributes and style tags.
+	redressPolicy struct {
+		config redressConfig
+	}
+
+	redressConfig struct {
+		maxWidth  float64
+		maxHeight float64
+		onHidden  HiddenContentHandler
+	}
+
+	// HiddenContent is a set of techniques used for hiding content from the user.
+	HiddenContent uint
+
+	// HiddenContentHandler handles tags hidden from the user, found by BlockUIRedressing.
+	HiddenContentHandler func(tag *Tag, reasons HiddenContent)
+)
+
+const (
+	// HiddenDisplay is set for display:none.
+	HiddenDisplay HiddenContent = 1 << iota
+	// HiddenVisibility is set for visibility:hidden and visibility:collapse.
+	HiddenVisibility
+	// HiddenFontSize is set for a zero font-size.
+	HiddenFontSize
+	// HiddenOpacity is set for a zero opacity.
+	HiddenOpacity
+	// HiddenColor is set when the text color is the same as the background color.
+	HiddenColor
+)
+
+// cssLengthUnits maps absolute CSS length units, and font relative units with the default font size, to pixels.
+var cssLengthUnits = map[string]float64{
+	"px":  1,
+	"em":  16,
+	"rem": 16,
+	"ex":  8,
+	"ch":  8,
+	"pt":  4.0 / 3,
+	"pc":  16,
+	"in":  96,
+	"cm":  96 / 2.54,
+	"mm":  96 / 25.4,
+	"q":   96 / 101.6,
+}
+
+// redressProperties are the CSS properties used for moving content over other elements.
+// Properties starting with redressPrefixes, like inset-block-start or offset-path, are removed as well.
+var redressProperties = map[string]struct{}{
+	"z-index":   {},
+	"top":       {},
+	"right":     {},
+	"bottom":    {},
+	"left":      {},
+	"inset":     {},
+	"offset":    {},
+	"transform": {},
+	"translate": {},
+	"scale":     {},
+	"rotate":    {},
+}
+
+// redressPrefixes are the prefixes of the logical inset and motion path properties.
+var redressPrefixes = []string{"inset-", "offset-"}
+
+// ClampDimensions limits widths and heights in style attributes, including their min and max variants,
+// to the given amount of pixels. Lengths in other units are converted with the default font size,
+// and lengths that cannot be converted, like calc(), are removed. Percentages are kept.
+//
+// Zero disables the limit for the dimension.
+func ClampDimensions(maxWidth, maxHeight int) RedressOption {
+	return func(c *redressConfig) {
+		c.maxWidth = float64(maxWidth)
+		c.maxHeight = float64(maxHeight)
+	}
+}
+
+// ReportHiddenContent receives a handler for tags hidden from the user, by display:none,
+// visibility:hidden, zero font size or opacity, or text with the same color as its background.
+//
+// Hidden content is not removed, handlers can block the tag, or annotate it.
+func ReportHiddenContent(handler HiddenContentHandler) RedressOption {
+	return func(c *redressConfig) {
+		c.onHidden = handler
+	}
+}
+
+// BlockUIRedressing removes CSS declarations from style attributes used for overlaying content
+// over the page, like fake login forms, or moving it outside of its container:
+//   - position, except static and relative.
+//   - z-index, top, right, bottom, left, inset and its inset-* longhands.
+//   - transform, translate, scale, rotate, and offset motion paths.
+//   - Margins with negative values.
+//   - Widths and heights in viewport units above 100.
+//
+// Style attributes without any remaining declarations are blocked. Rules in style tags are filtered
+// the same way, including rules nested in at-rules like @media, and removed if no declarations remain.
+// Hidden content is only reported for style attributes.
+//
+// Example:
+//
+//	sanitize.BlockUIRedressing(sanitize.ClampDimensions(800, 0), sanitize.ReportHiddenContent(report))
+func BlockUIRedressing(opts ...RedressOption) Policy {
+	var config redressConfig
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	return redressPolicy{config: config}
+}
+
+func (p redressPolicy) Apply(tag *Tag) {
+	for _, attr := range tag.attributes {
+		if attr.Key() != "style" || attr.IsDenied() {
+			continue
+		}
+
+		decls, escaped := parseStyle(attr.value)
+		kept := p.config.filter(decls)
+
+		if len(kept) == 0 {
+			attr.Block()
+			continue
+		}
+
+		if rendered := renderStyle(kept); escaped || rendered != renderStyle(decls) {
+			attr.SetValue(rendered)
+		}
+
+		if p.config.onHidden == nil {
+			continue
+		}
+
+		if reasons := hiddenContent(kept); reasons != 0 {
+			p.config.onHidden(tag, reasons)
+		}
+	}
+}
+
+func (p redressPolicy) ApplyText(text *Text) {
+	if text.IsComment() || text.parent == nil || text.parent.atom != atom.Style {
+		return
+	}
+
+	text.SetData(filterStylesheet(text.data, p.config.filter))
+}
+
+// filter returns the declarations that are kept, with their values clamped if needed.
+func (c redressConfig) filter(decls []styleDeclaration) []styleDeclaration {
+	kept := make([]styleDeclaration, 0, len(decls))
+
+	for _, decl := range decls {
+		if value, ok := c.redress(decl); ok {
+			decl.value = value
+			kept = append(kept, decl)
+		}
+	}
+
+	return kept
+}
+
+// redress returns the value of the declaration, clamped if needed, or false if it must be removed.
+func (c redressConfig) redress(decl styleDeclaration) (string, bool) {
+	if _, ok := redressProperties[decl.property]; ok {
+		return "", false
+	}
+
+	for _, prefix := range redressPrefixes {
+		if strings.HasPrefix(decl.property, prefix) {
+			return "", false
+		}
+	}
+
+	switch property := decl.property; {
+	case property == "position":
+		value := strings.ToLower(decl.value)
+		return value, value == "static" || value == "relative"
+	case strings.HasPrefix(property, "margin"):
+		for _, part := range splitCSS(decl.value, ' ') {
+			part = strings.TrimSpace(part)
+			if strings.HasPrefix(part, "-") || strings.Contains(part, "(") {
+				return "", false
+			}
+		}
+	case property == "width", property == "min-width", property == "max-width":
+		return clampLength(decl.value, c.maxWidth)
+	case property == "height", property == "min-height", property == "max-height":
+		return clampLength(decl.value, c.maxHeight)
+	}
+
+	return decl.value, true
+}
+
+// clampLength limits the length to limit pixels, removing viewport lengths above 100.
+// Keywords, like auto, and percentages are kept. Zero disables the pixel limit.
+func clampLength(value string, limit float64) (string, bool) {
+	number, unit := splitLength(value)
+
+	n, err := strconv.ParseFloat(number, 64)
+	if err != nil || strings.Trim(number, "0123456789.+-") != "" {
+		if isCSSKeyword(value) {
+			return value, true
+		}
+		return value, limit == 0
+	}
+
+	switch {
+	case n < 0:
+		return "", false
+	case unit == "%":
+		return value, true
+	case isViewportUnit(unit):
+		return value, n <= 100
+	case limit == 0:
+		return value, true
+	}
+
+	ratio, ok := cssLengthUnits[unit]
+	if !ok && n != 0 {
+		return "", false
+	}
+
+	if n*ratio > limit {
+		return strconv.FormatFloat(limit, 'f', -1, 64) + "px", true
+	}
+
+	return value, true
+}
+
+func isViewportUnit(unit string) bool {
+	switch unit {
+	case "vw", "vh", "vmin", "vmax", "svw", "svh", "lvw", "lvh", "dvw", "dvh":
+		return true
+	default:
+		return false
+	}
+}
+
+// splitLength splits a CSS length, like 10px, into its number and lower cased unit.
+func splitLength(value string) (string, string) {
+	value = strings.TrimSpace(value)
+
+	i := strings.LastIndexAny(value, "0123456789.")
+	return value[:i+1], strings.ToLower(value[i+1:])
+}
+
+// isCSSKeyword checks if the value is a single keyword, like auto or fit-content.
+func isCSSKeyword(value string) bool {
+	value = strings.TrimSpace(value)
+	if value == "" || value[0] == '-' {
+		return false
+	}
+
+	for _, r := range value {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '-') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// hiddenContent returns the techniques hiding the content styled by the declarations.
+func hiddenContent(decls []styleDeclaration) HiddenContent {
+	var (
+		reasons       HiddenContent
+		color         [4]uint8
+		background    [4]uint8
+		hasColor      bool
+		hasBackground bool
+	)
+
+	for _, decl := range decls {
+		value := strings.ToLower(decl.value)
+
+		switch decl.property {
+		case "display":
+			if value == "none" {
+				reasons |= HiddenDisplay
+			}
+		case "visibility":
+			if value == "hidden" || value == "collapse" {
+				reasons |= HiddenVisibility
+			}
+		case "font-size":
+			if isZeroLength(value) {
+				reasons |= HiddenFontSize
+			}
+		case "opacity":
+			if isZeroLength(strings.TrimSuffix(value, "%")) {
+				reasons |= HiddenOpacity
+			}
+		case "color":
+			color, hasColor = colorRGBA(value)
+		case "background-color", "background":
+			background, hasBackground = colorRGBA(value)
+		}
+	}
+
+	// Colors are compared by their channels, as the same color can be written as white, #fff or #ffffff.
+	if hasColor && hasBackground && color == background {
+		reasons |= HiddenColor
+	}
+
+	return reasons
+}
+
+// isZeroLength checks if the value is zero, with or without unit, like 0, 0.0 or 0px.
+func isZeroLength(value string) bool {
+	number, _ := splitLength(value)
+	n, err := strconv.ParseFloat(number, 64)
+	return err == nil && n == 0 && strings.Trim(number, "0.+-") == ""
+}
@@ -0,0 +1,100 @@
+package sanitize_test
+
+import (
+	"testing"
+
+	"github.com/sonalys/sanitize"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_BlockUIRedressing(t *testing.T) {
+	apply := func(policy sanitize.Policy, style string) *sanitize.Attribute {
+		tag := &sanitize.Tag{}
+		tag.UpsertAttr("", "style", style)
+		policy.Apply(tag)
+		return tag.Attrs()[0]
+	}
+
+	tests := []struct {
+		name  string
+		style string
+		want  string
+	}{
+		{name: "fixed position", style: "position:fixed;top:0;left:0;z-index:9999;color:red", want: "color:red"},
+		{name: "relative position", style: "position:Relative;top:-500px", want: "position:relative"},
+		{name: "logical insets", style: "position:relative;inset-block-start:-500px;inset-inline-end:0;inset-block:0;z-index:9", want: "position:relative"},
+		{name: "motion paths", style: "offset:path('M 0 0 L 100 100');offset-path:path('M 0 0');offset-distance:100%;color:red", want: "color:red"},
+		{name: "transforms", style: "transform:translate(0,-100px);translate:10px;color:red", want: "color:red"},
+		{name: "negative margins", style: "margin:0 -10px;margin-top:-1em;margin-left:calc(0px - 10px);margin-right:auto", want: "margin-right:auto"},
+		{name: "positive margins", style: "margin:0 auto 10px", want: "margin:0 auto 10px"},
+		{name: "viewport dimensions", style: "width:100vw;height:200vh;min-height:101dvh", want: "width:100vw"},
+		{name: "escaped properties", style: `posit\69on:fixed;z-ind\65x:9999;to/**/p:0;color:red`, want: "color:red"},
+		{name: "unclamped dimensions", style: "width:5000px;height:calc(100% - 10px)", want: "width:5000px;height:calc(100% - 10px)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, apply(sanitize.BlockUIRedressing(), tt.style).Value())
+		})
+	}
+
+	t.Run("should block empty styles", func(t *testing.T) {
+		attr := apply(sanitize.BlockUIRedressing(), "position:absolute;z-index:10")
+		require.True(t, attr.IsBlocked())
+	})
+
+	t.Run("should clamp dimensions", func(t *testing.T) {
+		policy := sanitize.BlockUIRedressing(sanitize.ClampDimensions(800, 600))
+
+		attr := apply(policy, "width:5000px;max-width:60em;min-height:10in;height:50%;max-height:auto;min-width:calc(100vw)")
+		require.Equal(t, "width:800px;max-width:800px;min-height:600px;height:50%;max-height:auto", attr.Value())
+
+		attr = apply(policy, "width:40em;height:7.5in")
+		require.Equal(t, "width:40em;height:600px", attr.Value())
+	})
+
+	t.Run("should filter style tags", func(t *testing.T) {
+		got := sanitizeWith(t,
+			`<style>.x{position:fixed;z-index:999;top:0} p{color:red;top:0}</style>`,
+			sanitize.ScopeStyles("#c"),
+			sanitize.BlockUIRedressing(),
+		)
+		require.Equal(t, `<html><head><style>#c p{color:red}</style></head><body></body></html>`, got)
+
+		got = sanitizeWith(t,
+			`<style>@import url(a.css); @media (max-width:600px){.y{transform:none} .z{margin:-5px;width:100vw}} .a{color:red; .b{inset-block-start:0}}</style>`,
+			sanitize.BlockUIRedressing(),
+		)
+		require.Equal(t, `<html><head><style>@import url(a.css);@media (max-width:600px){.z{width:100vw}}.a{color:red;}</style></head><body></body></html>`, got)
+	})
+
+	t.Run("should report hidden content", func(t *testing.T) {
+		var reasons []sanitize.HiddenContent
+		policy := sanitize.BlockUIRedressing(sanitize.ReportHiddenContent(func(tag *sanitize.Tag, hidden sanitize.HiddenContent) {
+			reasons = append(reasons, hidden)
+		}))
+
+		apply(policy, "display:none")
+		apply(policy, "visibility:hidden;font-size:0px")
+		apply(policy, "opacity:0.0")
+		apply(policy, "color:#FFF;background-color:#fff")
+		apply(policy, "color:white;background-color:#FFFFFF")
+		apply(policy, "color:rgb(100% 100% 100%);background:#ffff")
+		apply(policy, "color:hsl(0, 0%, 100%);background-color:rgba(255,255,255,1)")
+		apply(policy, "color:navy;background-color:#000080ff")
+		apply(policy, "color:red;background-color:white;font-size:12px;opacity:0.5")
+		apply(policy, "color:currentcolor;background-color:currentcolor")
+		apply(policy, "color:#fff;background-color:rgb(255 255 255 / 0)")
+
+		require.Equal(t, []sanitize.HiddenContent{
+			sanitize.HiddenDisplay,
+			sanitize.HiddenVisibility | sanitize.HiddenFontSize,
+			sanitize.HiddenOpacity,
+			sanitize.HiddenColor,
+			sanitize.HiddenColor,
+			sanitize.HiddenColor,
+			sanitize.HiddenColor,
+			sanitize.HiddenColor,
+		}, reasons)
+	})
+}
@@ -0,0 +1,140 @@
+package sanitize
+
+import (
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html/atom"
+)
+
+// TagMigration is called right after a tag is renamed.
+// It receives the tag's previous atom, allowing attributes to be migrated to the new tag.
+type TagMigration func(tag *Tag, from atom.Atom)
+
+// fontSizes maps the legacy font size attribute to CSS absolute sizes.
+var fontSizes = [...]string{
+	1: "x-small",
+	2: "small",
+	3: "medium",
+	4: "large",
+	5: "x-large",
+	6: "xx-large",
+	7: "xxx-large",
+}
+
+// RenameTags will rename tags according to the renames map, keeping atom and data consistent.
+// Migrations are called for each renamed tag, in order.
+//
+// Example:
+//
+//	sanitize.RenameTags(map[atom.Atom]atom.Atom{atom.B: atom.Strong})
+func RenameTags(renames map[atom.Atom]atom.Atom, migrations ...TagMigration) Policy {
+	return TagPolicy(func(tag *Tag) {
+		to, ok := renames[tag.atom]
+		if !ok {
+			return
+		}
+
+		from := tag.atom
+		tag.SetAtom(to)
+
+		for _, migration := range migrations {
+			migration(tag, from)
+		}
+	})
+}
+
+// MigrateAttrToStyle moves an attribute from tags previously named as the given atom into
+// a CSS property in the style attribute.
+//
+// Example:
+//
+//	sanitize.MigrateAttrToStyle(atom.Font, "color", "color")
+func MigrateAttrToStyle(from atom.Atom, key, property string) TagMigration {
+	key = Normalize(key)
+
+	return func(tag *Tag, previous atom.Atom) {
+		if previous != from {
+			return
+		}
+
+		value, ok := tag.attrValue(key)
+		tag.RemoveAttr(key)
+		if ok {
+			tag.SetStyle(property, value)
+		}
+	}
+}
+
+// MigrateFontAttrs moves the color, face and size attributes from font tags into their CSS equivalents.
+func MigrateFontAttrs() TagMigration {
+	color := MigrateAttrToStyle(atom.Font, "color", "color")
+	face := MigrateAttrToStyle(atom.Font, "face", "font-family")
+
+	return func(tag *Tag, from atom.Atom) {
+		if from != atom.Font {
+			return
+		}
+
+		color(tag, from)
+		face(tag, from)
+
+		value, ok := tag.attrValue("size")
+		tag.RemoveAttr("size")
+		if size, valid := fontSize(value); ok && valid {
+			tag.SetStyle("font-size", size)
+		}
+	}
+}
+
+// MigrateCenter aligns the content of renamed center tags.
+func MigrateCenter() TagMigration {
+	return func(tag *Tag, from atom.Atom) {
+		if from == atom.Center {
+			tag.SetStyle("text-align", "center")
+		}
+	}
+}
+
+// ModernizeTags renames legacy presentational tags into their modern equivalents:
+//
+//	b      -> strong
+//	i      -> em
+//	tt     -> code
+//	strike -> s
+//	font   -> span, with color, face and size converted to CSS
+//	center -> div, with centered text
+//
+// It should be declared before any tag whitelist, so the modern tags are the ones evaluated.
+func ModernizeTags() Policy {
+	return RenameTags(
+		map[atom.Atom]atom.Atom{
+			atom.B:      atom.Strong,
+			atom.I:      atom.Em,
+			atom.Tt:     atom.Code,
+			atom.Strike: atom.S,
+			atom.Font:   atom.Span,
+			atom.Center: atom.Div,
+		},
+		MigrateFontAttrs(),
+		MigrateCenter(),
+	)
+}
+
+// fontSize converts a legacy font size, like 5, +1 or -2, into a CSS absolute size.
+func fontSize(value string) (string, bool) {
+	value = strings.TrimSpace(value)
+
+	size, err := strconv.Atoi(value)
+	if err != nil {
+		return "", false
+	}
+
+	if strings.HasPrefix(value, "+") || strings.HasPrefix(value, "-") {
+		size += 3
+	}
+
+	size = min(max(size, 1), len(fontSizes)-1)
+
+	return fontSizes[size], true
+}
@@ -0,0 +1,45 @@
+package sanitize_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/sonalys/sanitize"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/html/atom"
+)
+
+func Test_RenameTags(t *testing.T) {
+	t.Run("should update atom and data", func(t *testing.T) {
+		tag := &sanitize.Tag{}
+		tag.SetAtom(atom.B)
+
+		sanitize.RenameTags(map[atom.Atom]atom.Atom{atom.B: atom.Strong}).Apply(tag)
+
+		require.Equal(t, atom.Strong, tag.Atom())
+		require.Equal(t, "strong", tag.Data())
+	})
+
+	t.Run("should be evaluated by subsequent policies", func(t *testing.T) {
+		content := []byte(`<b>bold</b>`)
+		out := bytes.NewBuffer(make([]byte, 0, 1024))
+		err := sanitize.HTML(bytes.NewReader(content), out,
+			sanitize.RenameTags(map[atom.Atom]atom.Atom{atom.B: atom.Strong}),
+			sanitize.BlockTags(atom.Strong),
+		)
+		require.NoError(t, err)
+
+		require.Equal(t, `<html><head></head><body></body></html>`, out.String())
+	})
+}
+
+func Test_ModernizeTags(t *testing.T) {
+	content := []byte(`<center><font color="red" face="Arial, 'Times New Roman'" size="+1">a</font><font color="red;position:fixed" size="x">b</font><b>c</b><i>d</i></center>`)
+	out := bytes.NewBuffer(make([]byte, 0, 1024))
+	err := sanitize.HTML(bytes.NewReader(content), out,
+		sanitize.ModernizeTags(),
+	)
+	require.NoError(t, err)
+
+	require.Equal(t, `<html><head></head><body><div style="text-align:center"><span style="color:red;font-family:Arial, &#39;Times New Roman&#39;;font-size:large">a</span><span>b</span><strong>c</strong><em>d</em></div></body></html>`, out.String())
+}
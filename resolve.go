@@ -0,0 +1,101 @@
+package sanitize
+
+import (
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html/atom"
+)
+
+// ResolveURLs will resolve all relative URLs against the given base,
+// so they don't resolve against the application rendering the content.
+// In-document base tags are ignored and denied.
+//
+// It applies to all URL attributes, srcset candidates and url() in style attributes.
+// Fragment only URLs, like #section, are kept as in-document references.
+func ResolveURLs(base *url.URL) Policy {
+	return Policies{
+		DenyTags(atom.Base),
+		resolveURLs(func(*Tag) *url.URL { return base }),
+	}
+}
+
+// ResolveURLsWithDocumentBase is like ResolveURLs, but honors the href of the
+// document's first base tag, resolved against the given base.
+// Like browsers, base tags must come before the URLs they resolve, usually in the head.
+//
+// Base tags are denied after being read, since all URLs are already resolved.
+// Only http and https document bases are honored, other bases are ignored.
+func ResolveURLsWithDocumentBase(base *url.URL) Policy {
+	return Policies{
+		When(OnTags(atom.Base), TagPolicy(func(tag *Tag) {
+			tag.Deny()
+
+			href, ok := tag.attrValue("href")
+			if !ok || tag.doc == nil || tag.doc.base != nil {
+				return
+			}
+
+			u, err := parseURL(href)
+			if err != nil {
+				return
+			}
+
+			// Bases like javascript: or data: would turn relative URLs into scripts or inline documents.
+			resolved := base.ResolveReference(u)
+			if scheme := strings.ToLower(resolved.Scheme); scheme != "http" && scheme != "https" {
+				return
+			}
+			tag.doc.base = resolved
+		})),
+		resolveURLs(func(tag *Tag) *url.URL {
+			if tag.doc != nil && tag.doc.base != nil {
+				return tag.doc.base
+			}
+			return base
+		}),
+	}
+}
+
+// BlockRelativeURLs will deny all URLs without a scheme, including scheme relative URLs like //host/path.
+// Fragment only URLs, like #section, are kept as in-document references.
+//
+// It applies to all URL attributes, srcset candidates and url() in style attributes.
+func BlockRelativeURLs() Policy {
+	return RewriteURLs(func(rawURL string) (string, bool) {
+		if strings.HasPrefix(rawURL, "#") {
+			return rawURL, true
+		}
+
+		u, err := parseURL(rawURL)
+		if err != nil {
+			return "", false
+		}
+
+		return rawURL, u.IsAbs()
+	})
+}
+
+func resolveURLs(baseFor func(*Tag) *url.URL) Policy {
+	return TagPolicy(func(tag *Tag) {
+		base := baseFor(tag)
+
+		tag.AttrPolicy(func(attr *Attribute) {
+			rewriteAttrURLs(attr, func(rawURL string) (string, bool) {
+				if strings.HasPrefix(rawURL, "#") {
+					return rawURL, true
+				}
+
+				u, err := parseURL(rawURL)
+				if err != nil {
+					return "", false
+				}
+				if u.IsAbs() {
+					return rawURL, true
+				}
+
+				return base.ResolveReference(u).String(), true
+			})
+		})
+	})
+}
@@ -0,0 +1,75 @@
+package sanitize_test
+
+import (
+	"bytes"
+	"net/url"
+	"testing"
+
+	"github.com/sonalys/sanitize"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ResolveURLs(t *testing.T) {
+	base, err := url.Parse("https://mail.example/messages/1/")
+	require.NoError(t, err)
+
+	content := []byte(`<html><head><base href="https://evil.com/"/></head><body><a href="page?a=1">1</a><a href="/root">2</a><a href="//cdn.example/x">3</a><a href="#top">4</a><img src="cid:a" srcset="a.png 1x, https://b.example/b.png 2x" style="background:url(bg.png)"/></body></html>`)
+	out := bytes.NewBuffer(make([]byte, 0, 1024))
+	err = sanitize.HTML(bytes.NewReader(content), out,
+		sanitize.ResolveURLs(base),
+	)
+	require.NoError(t, err)
+
+	require.Equal(t, `<html><head></head><body><a href="https://mail.example/messages/1/page?a=1">1</a><a href="https://mail.example/root">2</a><a href="https://cdn.example/x">3</a><a href="#top">4</a><img src="cid:a" srcset="https://mail.example/messages/1/a.png 1x, https://b.example/b.png 2x" style="background:url(&#34;https://mail.example/messages/1/bg.png&#34;)"/></body></html>`, out.String())
+}
+
+func Test_ResolveURLsWithDocumentBase(t *testing.T) {
+	base, err := url.Parse("https://mail.example/messages/1/")
+	require.NoError(t, err)
+
+	content := []byte(`<html><head><base href="../2/"/><base href="https://evil.com/"/></head><body><a href="page">1</a></body></html>`)
+	out := bytes.NewBuffer(make([]byte, 0, 1024))
+	err = sanitize.HTML(bytes.NewReader(content), out,
+		sanitize.ResolveURLsWithDocumentBase(base),
+	)
+	require.NoError(t, err)
+
+	require.Equal(t, `<html><head></head><body><a href="https://mail.example/messages/2/page">1</a></body></html>`, out.String())
+}
+
+func Test_ResolveURLsWithDocumentBase_UnsafeSchemes(t *testing.T) {
+	base, err := url.Parse("https://mail.example/messages/1/")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		href string
+	}{
+		{name: "javascript", href: "javascript:0"},
+		{name: "data", href: "data:text/html,x"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := []byte(`<html><head><base href="` + tt.href + `"/></head><body><a href="x%0Aalert(document.domain)">1</a></body></html>`)
+			out := bytes.NewBuffer(make([]byte, 0, 1024))
+			err := sanitize.HTML(bytes.NewReader(content), out,
+				sanitize.ResolveURLsWithDocumentBase(base),
+			)
+			require.NoError(t, err)
+
+			require.Equal(t, `<html><head></head><body><a href="https://mail.example/messages/1/x%0Aalert(document.domain)">1</a></body></html>`, out.String())
+		})
+	}
+}
+
+func Test_BlockRelativeURLs(t *testing.T) {
+	content := []byte(`<a href="page">1</a><a href="//cdn.example/x">2</a><a href="#top">3</a><a href="mailto:a@b.c">4</a><img src="cid:a"/>`)
+	out := bytes.NewBuffer(make([]byte, 0, 1024))
+	err := sanitize.HTML(bytes.NewReader(content), out,
+		sanitize.BlockRelativeURLs(),
+	)
+	require.NoError(t, err)
+
+	require.Equal(t, `<html><head></head><body><a>1</a><a>2</a><a href="#top">3</a><a href="mailto:a@b.c">4</a><img src="cid:a"/></body></html>`, out.String())
+}
@@ -2,15 +2,68 @@ package sanitize
 
 import (
 	"io"
+	"net/url"
 	"slices"
+	"strings"
 
 	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
 )
 
-func sanitizeNode(node *html.Node, policies ...Policy) {
+type (
+	// document holds the state shared by all tags of a sanitized document.
+	document struct {
+		// base is the URL relative URLs are resolved against, when resolving URLs.
+		base *url.URL
+		// originals are the attributes of each element before it was sanitized.
+		originals map[*html.Node][]html.Attribute
+	}
+
+	// documentApplier is implemented by policies transforming the whole document after it was sanitized.
+	// Changed elements must be evaluated again by the policies.
+	documentApplier interface {
+		applyDocument(root *html.Node, doc *document, policies Policies)
+	}
+)
+
+func (p Policies) applyDocument(root *html.Node, doc *document, policies Policies) {
+	for _, policy := range p {
+		if applier, ok := policy.(documentApplier); ok {
+			applier.applyDocument(root, doc, policies)
+		}
+	}
+}
+
+func (p finalPolicy) applyDocument(root *html.Node, doc *document, policies Policies) {
+	if applier, ok := p.Policy.(documentApplier); ok {
+		applier.applyDocument(root, doc, policies)
+	}
+}
+
+// applyPolicies applies the policies to the tag, followed by its final policies.
+func applyPolicies(tag *Tag, policies []Policy) {
+	for _, policy := range policies {
+		policy.Apply(tag)
+	}
+
+	// Final policies can also declare final policies, which are applied right after.
+	for i := 0; i < len(tag.finals); i++ {
+		tag.finals[i].Apply(tag)
+	}
+}
+
+// sanitizeNode applies the policies to the node and all its descendants.
+// Inserted is true for content added by policies, which cannot insert more content,
+// preventing policies from recursively inserting content into themselves.
+func sanitizeNode(doc *document, node *html.Node, parent *Tag, inserted bool, policies ...Policy) {
+	if node.Type == html.TextNode || node.Type == html.CommentNode {
+		sanitizeText(node, parent, policies...)
+		return
+	}
+
 	if node.Type != html.ElementNode {
 		for _, node := range slices.Collect(node.ChildNodes()) {
-			sanitizeNode(node, policies...)
+			sanitizeNode(doc, node, parent, inserted, policies...)
 		}
 		return
 	}
@@ -19,23 +72,108 @@ func sanitizeNode(node *html.Node, policies ...Policy) {
 		atom:       node.DataAtom,
 		data:       node.Data,
 		attributes: fromAttrs(node.Attr),
+		parent:     parent,
+		node:       node,
+		original:   node.Attr,
+		doc:        doc,
 	}
 
-	for _, policy := range policies {
-		policy.Apply(tag)
+	doc.originals[node] = node.Attr
+	applyPolicies(tag, policies)
+
+	if tag.replacement != nil && !inserted {
+		context := node.Parent
+		if context == nil || context.Type != html.ElementNode {
+			context = &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body}
+		}
+
+		for _, child := range parseFragment(*tag.replacement, context) {
+			node.Parent.InsertBefore(child, node)
+			sanitizeNode(doc, child, parent, true, policies...)
+		}
+
+		node.Parent.RemoveChild(node)
+		return
+	}
+
+	if tag.unwrapped && !tag.IsDenied() {
+		for _, child := range slices.Collect(node.ChildNodes()) {
+			node.RemoveChild(child)
+			node.Parent.InsertBefore(child, node)
+			sanitizeNode(doc, child, parent, inserted, policies...)
+		}
+
+		node.Parent.RemoveChild(node)
+		return
 	}
 
-	if tag.blocked {
+	if tag.IsBlocked() {
 		node.Parent.RemoveChild(node)
 		return
 	}
 
 	node.Data = tag.data
+	node.DataAtom = tag.atom
 	node.Attr = toAttrs(tag.attributes)
 
-	for _, node := range slices.Collect(node.ChildNodes()) {
-		sanitizeNode(node, policies...)
+	if tag.text != nil {
+		for child := node.FirstChild; child != nil; child = node.FirstChild {
+			node.RemoveChild(child)
+		}
+		node.AppendChild(&html.Node{Type: html.TextNode, Data: *tag.text})
 	}
+
+	added := make(map[*html.Node]struct{})
+
+	if !inserted {
+		for _, content := range slices.Backward(tag.prepend) {
+			for _, child := range slices.Backward(parseFragment(content, node)) {
+				node.InsertBefore(child, node.FirstChild)
+				added[child] = struct{}{}
+			}
+		}
+
+		for _, content := range tag.append {
+			for _, child := range parseFragment(content, node) {
+				node.AppendChild(child)
+				added[child] = struct{}{}
+			}
+		}
+	}
+
+	for _, child := range slices.Collect(node.ChildNodes()) {
+		_, isAdded := added[child]
+		sanitizeNode(doc, child, tag, inserted || isAdded, policies...)
+	}
+}
+
+func sanitizeText(node *html.Node, parent *Tag, policies ...Policy) {
+	text := &Text{
+		data:    node.Data,
+		parent:  parent,
+		comment: node.Type == html.CommentNode,
+	}
+
+	Policies(policies).ApplyText(text)
+
+	// Final policies can also declare final policies, which are applied right after.
+	for i := 0; i < len(text.finals); i++ {
+		text.finals[i].ApplyText(text)
+	}
+
+	if text.IsBlocked() {
+		node.Parent.RemoveChild(node)
+		return
+	}
+
+	node.Data = text.data
+}
+
+// parseFragment parses HTML content in the context of the given element.
+func parseFragment(content string, context *html.Node) []*html.Node {
+	// Reading from a strings.Reader never fails.
+	nodes, _ := html.ParseFragment(strings.NewReader(content), context)
+	return nodes
 }
 
 // HTML will sanitize the HTML content for the given policies.
@@ -46,6 +184,8 @@ func HTML(r io.Reader, w io.Writer, policies ...Policy) error {
 	if err != nil {
 		return err
 	}
-	sanitizeNode(node, policies...)
+	doc := &document{originals: make(map[*html.Node][]html.Attribute)}
+	sanitizeNode(doc, node, nil, false, policies...)
+	Policies(policies).applyDocument(node, doc, policies)
 	return html.Render(w, node)
 }
@@ -8,6 +8,7 @@ import (
 
 	"github.com/sonalys/sanitize"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/net/html/atom"
 )
 
 func TestSanitize(t *testing.T) {
@@ -51,3 +52,59 @@ func TestSanitize(t *testing.T) {
 		require.Equal(t, `<html><head></head><body></body></html>`, writer.String())
 	})
 }
+
+// sanitizeWith sanitizes the content with the policies, returning the sanitized output.
+func sanitizeWith(t *testing.T, content string, policies ...sanitize.Policy) string {
+	t.Helper()
+
+	out := bytes.NewBuffer(make([]byte, 0, 1024))
+	err := sanitize.HTML(strings.NewReader(content), out, policies...)
+	require.NoError(t, err)
+	return out.String()
+}
+
+func TestSanitize_Mutations(t *testing.T) {
+	t.Run("replace denied tag with a link", func(t *testing.T) {
+		got := sanitizeWith(t, `<iframe src="http://video"></iframe>`,
+			sanitize.DenyDangerousTags(),
+			sanitize.When(sanitize.OnTags(atom.Iframe), sanitize.TagPolicy(func(tag *sanitize.Tag) {
+				tag.ReplaceWith(`<a href="http://video">open video</a><script></script>`)
+			})),
+		)
+		require.Equal(t, `<html><head></head><body><a href="http://video">open video</a></body></html>`, got)
+	})
+
+	t.Run("inserted content is sanitized", func(t *testing.T) {
+		got := sanitizeWith(t, `<div><p>content</p></div>`,
+			sanitize.BlockAttrs("onclick"),
+			sanitize.When(sanitize.OnTags(atom.Div), sanitize.TagPolicy(func(tag *sanitize.Tag) {
+				tag.PrependHTML(`<p onclick="alert(1)">warning</p>`)
+				tag.PrependHTML(`<p>first</p>`)
+				tag.AppendHTML(`<div>footer</div>`)
+			})),
+		)
+		require.Equal(t, `<html><head></head><body><div><p>warning</p><p>first</p><p>content</p><div>footer</div></div></body></html>`, got)
+	})
+
+	t.Run("set text", func(t *testing.T) {
+		got := sanitizeWith(t, `<a href="#">click <b>here</b></a>`,
+			sanitize.When(sanitize.OnTags(atom.A), sanitize.TagPolicy(func(tag *sanitize.Tag) {
+				tag.SetText("<b>safe</b>")
+			})),
+		)
+		require.Equal(t, `<html><head></head><body><a href="#">&lt;b&gt;safe&lt;/b&gt;</a></body></html>`, got)
+	})
+}
+
+func TestSanitize_Unwrap(t *testing.T) {
+	content := `<div><span onclick="a()">keep <b onclick="b()">this</b></span><i><p>denied</p></i></div>`
+	out := bytes.NewBuffer(make([]byte, 0, 1024))
+	err := sanitize.HTML(strings.NewReader(content), out,
+		sanitize.BlockAttrs("onclick"),
+		sanitize.When(sanitize.OnTags(atom.Span, atom.I), sanitize.TagPolicy((*sanitize.Tag).Block), sanitize.TagPolicy((*sanitize.Tag).Unwrap)),
+		sanitize.DenyTags(atom.I),
+	)
+	require.NoError(t, err)
+
+	require.Equal(t, `<html><head></head><body><div>keep <b>this</b></div></body></html>`, out.String())
+}
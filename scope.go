@@ -0,0 +1,380 @@
+package sanitize
+
+import (
+	"strings"
+
+	"golang.org/x/net/html/atom"
+)
+
+// cssRule is a top level statement of a stylesheet, like a qualified rule or an at-rule.
+type cssRule struct {
+	prelude  string
+	block    string
+	hasBlock bool
+}
+
+// ScopeStyles rewrites the stylesheets in style tags, so their rules only apply inside the container:
+//   - Selectors are prefixed with the container, like "p" into "#message p".
+//   - Selectors targeting :root, html or body are rewritten to target the container's content,
+//     like "body > p" into "#message > p", and removed if they target them directly.
+//   - Selectors starting with sibling combinators, like "+ nav" or "body ~ div", are removed,
+//     as they would match elements outside the container.
+//   - @media, @supports, @container and @layer rules are scoped recursively.
+//   - @font-face rules loading fonts from URLs other than data URLs are removed.
+//   - @import and other at-rules, like @keyframes, @page or @namespace, are removed,
+//     as they cannot be scoped.
+//
+// Comments are removed, and malformed content, like unterminated strings or blocks, is discarded.
+// The container must be a valid selector, see ParseSelector, or ScopeStyles panics.
+//
+// Example:
+//
+//	sanitize.ScopeStyles("#email-body")
+func ScopeStyles(container string) Policy {
+	container = MustParseSelector(container).String()
+
+	return TextPolicy(func(text *Text) {
+		if text.IsComment() || text.parent == nil || text.parent.atom != atom.Style {
+			return
+		}
+
+		text.SetData(scopeStylesheet(text.data, container))
+	})
+}
+
+// scopeStylesheet scopes all rules of the stylesheet under the container.
+func scopeStylesheet(css, container string) string {
+	var b strings.Builder
+
+	for _, rule := range parseStylesheet(stripCSSComments(css)) {
+		prelude := strings.TrimSpace(rule.prelude)
+
+		if !strings.HasPrefix(prelude, "@") {
+			selectors := scopeSelectors(prelude, container)
+			if selectors == "" || !rule.hasBlock {
+				continue
+			}
+
+			b.WriteString(selectors)
+			b.WriteString("{")
+			b.WriteString(strings.TrimSpace(rule.block))
+			b.WriteString("}")
+			continue
+		}
+
+		name := strings.ToLower(prelude[1:])
+		if i := strings.IndexFunc(name, func(r rune) bool { return r < 0x80 && !isIdentChar(byte(r)) }); i >= 0 {
+			name = name[:i]
+		}
+
+		switch {
+		case rule.hasBlock && (name == "media" || name == "supports" || name == "container" || name == "layer"):
+			b.WriteString(prelude)
+			b.WriteString("{")
+			b.WriteString(scopeStylesheet(rule.block, container))
+			b.WriteString("}")
+		case name == "layer":
+			b.WriteString(prelude)
+			b.WriteString(";")
+		case rule.hasBlock && name == "font-face":
+			if _, ok := rewriteCSSURLs(rule.block, isDataURL); !ok {
+				continue
+			}
+
+			b.WriteString(prelude)
+			b.WriteString("{")
+			b.WriteString(strings.TrimSpace(rule.block))
+			b.WriteString("}")
+		}
+	}
+
+	return b.String()
+}
+
+func isDataURL(rawURL string) (string, bool) {
+	u, err := parseURL(rawURL)
+	return rawURL, err == nil && strings.EqualFold(u.Scheme, "data")
+}
+
+// filterStylesheet applies the filter to the declarations of each rule in the stylesheet, including
+// rules nested in at-rules, like @media, or in other rules. Rules without remaining declarations are removed,
+// while statements like @import are kept. Comments and malformed content are discarded.
+func filterStylesheet(css string, filter func([]styleDeclaration) []styleDeclaration) string {
+	var b strings.Builder
+
+	for _, rule := range parseStylesheet(stripCSSComments(css)) {
+		prelude := strings.TrimSpace(rule.prelude)
+
+		if !rule.hasBlock {
+			if strings.HasPrefix(prelude, "@") {
+				b.WriteString(prelude)
+				b.WriteString(";")
+				continue
+			}
+
+			// Declarations next to nested rules, like "color:red; .a{}".
+			decls, _ := parseStyle(prelude)
+			if kept := renderStyle(filter(decls)); kept != "" {
+				b.WriteString(kept)
+				b.WriteString(";")
+			}
+			continue
+		}
+
+		var block string
+		if hasNestedRules(rule.block) {
+			block = filterStylesheet(rule.block, filter)
+		} else {
+			decls, _ := parseStyle(rule.block)
+			block = renderStyle(filter(decls))
+		}
+
+		if block == "" {
+			continue
+		}
+
+		b.WriteString(prelude)
+		b.WriteString("{")
+		b.WriteString(block)
+		b.WriteString("}")
+	}
+
+	return b.String()
+}
+
+// hasNestedRules checks if the block contains rules, like the block of @media, instead of only declarations.
+func hasNestedRules(block string) bool {
+	for _, rule := range parseStylesheet(block) {
+		if rule.hasBlock {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseStylesheet splits the stylesheet into its top level rules.
+// Parsing stops at the first malformed rule, like unterminated strings or unbalanced blocks.
+func parseStylesheet(css string) []cssRule {
+	var (
+		rules      []cssRule
+		quote      byte
+		depth      int
+		start      int
+		preludeEnd int
+	)
+
+	for i := 0; i < len(css); i++ {
+		c := css[i]
+
+		switch {
+		case quote != 0:
+			switch c {
+			case '\\':
+				i++
+			case '\n', '\r', '\f':
+				// Strings cannot contain new lines, browsers would recover differently.
+				return rules
+			case quote:
+				quote = 0
+			}
+		case c == '\\':
+			i++
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '{':
+			if depth == 0 {
+				preludeEnd = i
+			}
+			depth++
+		case c == '}':
+			depth--
+			if depth < 0 {
+				return rules
+			}
+			if depth == 0 {
+				rules = append(rules, cssRule{prelude: css[start:preludeEnd], block: css[preludeEnd+1 : i], hasBlock: true})
+				start = i + 1
+			}
+		case c == ';' && depth == 0:
+			rules = append(rules, cssRule{prelude: css[start:i]})
+			start = i + 1
+		}
+	}
+
+	if quote != 0 || depth != 0 {
+		return rules
+	}
+
+	if rest := strings.TrimSpace(css[start:]); rest != "" {
+		rules = append(rules, cssRule{prelude: rest})
+	}
+
+	return rules
+}
+
+// stripCSSComments removes comments, and the <!-- and --> markers, from the stylesheet.
+// Content after unterminated comments is removed.
+func stripCSSComments(css string) string {
+	var (
+		b     strings.Builder
+		quote byte
+	)
+
+	for i := 0; i < len(css); i++ {
+		c := css[i]
+
+		switch {
+		case quote != 0:
+			if c == '\\' && i+1 < len(css) {
+				b.WriteByte(c)
+				i++
+				c = css[i]
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '\\' && i+1 < len(css):
+			b.WriteByte(c)
+			i++
+			c = css[i]
+		case strings.HasPrefix(css[i:], "/*"):
+			end := strings.Index(css[i+2:], "*/")
+			if end < 0 {
+				return b.String()
+			}
+			i += end + 3
+			c = ' '
+		case strings.HasPrefix(css[i:], "<!--"):
+			i += len("<!--") - 1
+			c = ' '
+		case strings.HasPrefix(css[i:], "-->"):
+			i += len("-->") - 1
+			c = ' '
+		}
+
+		b.WriteByte(c)
+	}
+
+	return b.String()
+}
+
+// scopeSelectors scopes a comma separated list of selectors, returning empty if none remain.
+func scopeSelectors(selectors, container string) string {
+	var scoped []string
+
+	for _, selector := range splitCSS(selectors, ',') {
+		if selector, ok := scopeSelector(strings.TrimSpace(selector), container); ok {
+			scoped = append(scoped, selector)
+		}
+	}
+
+	return strings.Join(scoped, ",")
+}
+
+// scopeSelector prefixes the selector with the container, removing leading :root, html and body compounds.
+// It returns false for selectors targeting the root elements directly, or inside the container.
+func scopeSelector(selector, container string) (string, bool) {
+	var (
+		compounds   []string
+		combinators []string
+		pending     string
+		cur         strings.Builder
+		quote       byte
+		depth       int
+	)
+
+	flush := func() {
+		if cur.Len() > 0 {
+			compounds = append(compounds, cur.String())
+			combinators = append(combinators, pending)
+			pending = ""
+			cur.Reset()
+		}
+	}
+
+	for i := 0; i < len(selector); i++ {
+		c := selector[i]
+
+		switch {
+		case quote != 0:
+			if c == '\\' && i+1 < len(selector) {
+				cur.WriteByte(c)
+				i++
+				c = selector[i]
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\\' && i+1 < len(selector):
+			cur.WriteByte(c)
+			i++
+			c = selector[i]
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '(' || c == '[':
+			depth++
+		case c == ')' || c == ']':
+			depth--
+		case depth == 0 && isSpace(c):
+			flush()
+			if pending == "" && len(compounds) > 0 {
+				pending = " "
+			}
+			continue
+		case depth == 0 && (c == '>' || c == '+' || c == '~'):
+			flush()
+			pending = string(c)
+			continue
+		}
+
+		cur.WriteByte(c)
+	}
+	flush()
+
+	if len(compounds) == 0 || quote != 0 || depth != 0 || pending != "" && pending != " " {
+		return "", false
+	}
+
+	// The combinator after the removed root compounds is kept, like "body > p" into "container > p".
+	for len(compounds) > 0 && isRootCompound(compounds[0]) {
+		compounds = compounds[1:]
+		combinators = combinators[1:]
+	}
+
+	// Sibling combinators would match elements next to the container, like "body ~ div" or "+ nav".
+	if len(compounds) == 0 || combinators[0] == "~" || combinators[0] == "+" {
+		return "", false
+	}
+
+	var b strings.Builder
+	b.WriteString(container)
+
+	for i, compound := range compounds {
+		if isRootCompound(compound) {
+			return "", false
+		}
+
+		switch combinators[i] {
+		case "", " ":
+			b.WriteByte(' ')
+		default:
+			b.WriteString(" " + combinators[i] + " ")
+		}
+		b.WriteString(compound)
+	}
+
+	return b.String(), true
+}
+
+// isRootCompound checks if the compound selector targets the root elements, like html.dark or :root.
+func isRootCompound(compound string) bool {
+	compound = strings.ToLower(compound)
+
+	name := compound
+	if i := strings.IndexAny(compound, ".#[:"); i >= 0 {
+		name = compound[:i]
+	}
+
+	return name == "html" || name == "body" || strings.Contains(compound, ":root")
+}
@@ -0,0 +1,88 @@
+package sanitize_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/sonalys/sanitize"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ScopeStyles(t *testing.T) {
+	scope := func(t *testing.T, css string) string {
+		out := bytes.NewBuffer(make([]byte, 0, 1024))
+		err := sanitize.HTML(bytes.NewReader([]byte(`<style>`+css+`</style>`)), out, sanitize.ScopeStyles("#message"))
+		require.NoError(t, err)
+
+		got := out.String()
+		got = got[len(`<html><head><style>`):]
+		return got[:len(got)-len(`</style></head><body></body></html>`)]
+	}
+
+	tests := []struct {
+		name string
+		css  string
+		want string
+	}{
+		{
+			name: "should prefix selectors",
+			css:  "p, .a > b{color:red}\n#x ~ i:not(.y, .z) { margin: 0 }",
+			want: "#message p,#message .a > b{color:red}#message #x ~ i:not(.y, .z){margin: 0}",
+		},
+		{
+			name: "should remove root compounds",
+			css:  "body{display:none} html.dark body > p, :root .x{color:red} .x body{color:blue}",
+			want: "#message > p,#message .x{color:red}",
+		},
+		{
+			name: "should remove sibling selectors outside the container",
+			css:  "body ~ div, html + nav, + nav, ~ p{display:none} a ~ b, body > p + i{color:red}",
+			want: "#message a ~ b,#message > p + i{color:red}",
+		},
+		{
+			name: "should scope nested rules",
+			css:  "@media (max-width: 600px){ body{margin:0} td{width:100%} } @supports (display:grid){div{display:grid}}",
+			want: "@media (max-width: 600px){#message td{width:100%}}@supports (display:grid){#message div{display:grid}}",
+		},
+		{
+			name: "should remove imports and global at-rules",
+			css:  `@import url("https://evil.example/a.css"); @charset "utf-8"; @keyframes spin{to{transform:rotate(1turn)}} @page{margin:0} a{color:red}`,
+			want: "#message a{color:red}",
+		},
+		{
+			name: "should filter font faces",
+			css:  `@font-face{font-family:a;src:url(https://evil.example/a.woff)} @font-face{font-family:b;src:url("data:font/woff;base64,AA")}`,
+			want: `@font-face{font-family:b;src:url("data:font/woff;base64,AA")}`,
+		},
+		{
+			name: "should remove comments",
+			css:  "<!-- /* body{} */ a{color:red} /* b */ -->",
+			want: "#message a{color:red}",
+		},
+		{
+			name: "should handle strings",
+			css:  `a[title="}{body"]{content:"}"} b{color:red}`,
+			want: `#message a[title="}{body"]{content:"}"}#message b{color:red}`,
+		},
+		{
+			name: "should drop malformed content",
+			css:  "a{color:red} b{content:\"x\ny\"} c{color:blue}",
+			want: "#message a{color:red}",
+		},
+		{
+			name: "should drop unbalanced blocks",
+			css:  "a{color:red} b{color:blue",
+			want: "#message a{color:red}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, scope(t, tt.css))
+		})
+	}
+
+	t.Run("should panic on invalid containers", func(t *testing.T) {
+		require.Panics(t, func() { sanitize.ScopeStyles("#") })
+	})
+}
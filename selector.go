@@ -0,0 +1,426 @@
+package sanitize
+
+import (
+	"fmt"
+	"strings"
+)
+
+type (
+	// Selector is a compiled CSS selector.
+	//
+	// It supports type, universal, class, id and attribute selectors,
+	// descendant and child combinators and selector groups:
+	//
+	//	table.newsletter > tr td[bgcolor], #footer a[href^="http"]
+	Selector struct {
+		raw    string
+		groups []complexSelector
+	}
+
+	// complexSelector is a chain of compound selectors, stored from right to left.
+	complexSelector struct {
+		compounds   []compoundSelector
+		combinators []byte
+	}
+
+	compoundSelector struct {
+		// tag is the lower case tag name, empty for the universal selector.
+		tag     string
+		ids     []string
+		classes []string
+		attrs   []attrSelector
+	}
+
+	attrSelector struct {
+		key             string
+		operator        string
+		value           string
+		caseInsensitive bool
+	}
+
+	selectorParser struct {
+		input string
+		pos   int
+	}
+)
+
+const (
+	combinatorDescendant = ' '
+	combinatorChild      = '>'
+)
+
+// ParseSelector compiles a CSS selector that can be used for matching tags.
+func ParseSelector(selector string) (*Selector, error) {
+	parser := &selectorParser{input: selector}
+
+	groups, err := parser.parseGroups()
+	if err != nil {
+		return nil, fmt.Errorf("invalid selector %q: %w", selector, err)
+	}
+
+	return &Selector{
+		raw:    selector,
+		groups: groups,
+	}, nil
+}
+
+// MustParseSelector is like ParseSelector, but panics if the selector is invalid.
+func MustParseSelector(selector string) *Selector {
+	s, err := ParseSelector(selector)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// Select applies the policies only to tags matching the CSS selector.
+// It panics if the selector is invalid.
+//
+// Tag names are matched as renamed by previous policies, like RenameTags, while classes, ids
+// and other attributes are matched as found in the document, even if previous policies blocked
+// or changed them. Attributes are fixed so blocking a class doesn't change which tags are selected,
+// while renames are followed so selectors target the tag that is rendered.
+//
+// Example:
+//
+//	sanitize.Select("table.newsletter td[bgcolor]", sanitize.BlockAttrs("bgcolor"))
+func Select(selector string, policies ...Policy) Policy {
+	return When(MustParseSelector(selector).Match, policies...)
+}
+
+func (s *Selector) String() string {
+	return s.raw
+}
+
+// Match checks if the tag matches the selector.
+// Tag names are matched after renames, and attributes as found in the document, like in Select.
+func (s *Selector) Match(tag *Tag) bool {
+	for i := range s.groups {
+		if s.groups[i].match(tag) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *complexSelector) match(tag *Tag) bool {
+	return c.matchFrom(0, tag)
+}
+
+func (c *complexSelector) matchFrom(i int, tag *Tag) bool {
+	if !c.compounds[i].match(tag) {
+		return false
+	}
+
+	if i == len(c.compounds)-1 {
+		return true
+	}
+
+	switch c.combinators[i] {
+	case combinatorChild:
+		return tag.parent != nil && c.matchFrom(i+1, tag.parent)
+	default:
+		for ancestor := tag.parent; ancestor != nil; ancestor = ancestor.parent {
+			if c.matchFrom(i+1, ancestor) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func (c *compoundSelector) match(tag *Tag) bool {
+	if c.tag != "" && c.tag != strings.ToLower(tag.data) {
+		return false
+	}
+
+	for _, id := range c.ids {
+		if value, ok := tag.originalAttrValue("id"); !ok || value != id {
+			return false
+		}
+	}
+
+	if len(c.classes) > 0 {
+		value, _ := tag.originalAttrValue("class")
+		classes := strings.Fields(value)
+		for _, class := range c.classes {
+			if !containsToken(classes, class) {
+				return false
+			}
+		}
+	}
+
+	for i := range c.attrs {
+		if !c.attrs[i].match(tag) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (a *attrSelector) match(tag *Tag) bool {
+	value, ok := tag.originalAttrValue(a.key)
+	if !ok {
+		return false
+	}
+
+	expected := a.value
+	if a.caseInsensitive {
+		value = strings.ToLower(value)
+		expected = strings.ToLower(expected)
+	}
+
+	switch a.operator {
+	case "":
+		return true
+	case "=":
+		return value == expected
+	case "~=":
+		return containsToken(strings.Fields(value), expected)
+	case "|=":
+		return value == expected || strings.HasPrefix(value, expected+"-")
+	case "^=":
+		return expected != "" && strings.HasPrefix(value, expected)
+	case "$=":
+		return expected != "" && strings.HasSuffix(value, expected)
+	case "*=":
+		return expected != "" && strings.Contains(value, expected)
+	default:
+		return false
+	}
+}
+
+func containsToken(tokens []string, token string) bool {
+	for _, cur := range tokens {
+		if cur == token {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *selectorParser) parseGroups() ([]complexSelector, error) {
+	var groups []complexSelector
+
+	for {
+		p.skipSpaces()
+
+		group, err := p.parseComplex()
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, group)
+
+		p.skipSpaces()
+		if p.eof() {
+			return groups, nil
+		}
+		if p.input[p.pos] != ',' {
+			return nil, fmt.Errorf("unexpected %q at position %d", p.input[p.pos], p.pos)
+		}
+		p.pos++
+	}
+}
+
+func (p *selectorParser) parseComplex() (complexSelector, error) {
+	var (
+		compounds   []compoundSelector
+		combinators []byte
+	)
+
+	for {
+		compound, err := p.parseCompound()
+		if err != nil {
+			return complexSelector{}, err
+		}
+		compounds = append(compounds, compound)
+
+		hasSpace := p.skipSpaces()
+		if p.eof() || p.input[p.pos] == ',' {
+			break
+		}
+
+		switch p.input[p.pos] {
+		case combinatorChild:
+			p.pos++
+			p.skipSpaces()
+			combinators = append(combinators, combinatorChild)
+		default:
+			if !hasSpace {
+				return complexSelector{}, fmt.Errorf("unexpected %q at position %d", p.input[p.pos], p.pos)
+			}
+			combinators = append(combinators, combinatorDescendant)
+		}
+	}
+
+	// Matching starts from the subject of the selector, the rightmost compound.
+	for i, j := 0, len(compounds)-1; i < j; i, j = i+1, j-1 {
+		compounds[i], compounds[j] = compounds[j], compounds[i]
+	}
+	for i, j := 0, len(combinators)-1; i < j; i, j = i+1, j-1 {
+		combinators[i], combinators[j] = combinators[j], combinators[i]
+	}
+
+	return complexSelector{
+		compounds:   compounds,
+		combinators: combinators,
+	}, nil
+}
+
+func (p *selectorParser) parseCompound() (compoundSelector, error) {
+	var compound compoundSelector
+	start := p.pos
+
+	switch {
+	case p.eof():
+		return compound, fmt.Errorf("expected selector at position %d", p.pos)
+	case p.input[p.pos] == '*':
+		p.pos++
+	case isIdentChar(p.input[p.pos]):
+		compound.tag = strings.ToLower(p.parseIdent())
+	}
+
+	for !p.eof() {
+		switch p.input[p.pos] {
+		case '.':
+			p.pos++
+			class := p.parseIdent()
+			if class == "" {
+				return compound, fmt.Errorf("expected class name at position %d", p.pos)
+			}
+			compound.classes = append(compound.classes, class)
+		case '#':
+			p.pos++
+			id := p.parseIdent()
+			if id == "" {
+				return compound, fmt.Errorf("expected id at position %d", p.pos)
+			}
+			compound.ids = append(compound.ids, id)
+		case '[':
+			p.pos++
+			attr, err := p.parseAttr()
+			if err != nil {
+				return compound, err
+			}
+			compound.attrs = append(compound.attrs, attr)
+		default:
+			if p.pos == start {
+				return compound, fmt.Errorf("unexpected %q at position %d", p.input[p.pos], p.pos)
+			}
+			return compound, nil
+		}
+	}
+
+	return compound, nil
+}
+
+func (p *selectorParser) parseAttr() (attrSelector, error) {
+	var attr attrSelector
+
+	p.skipSpaces()
+	attr.key = Normalize(p.parseIdent())
+	if attr.key == "" {
+		return attr, fmt.Errorf("expected attribute name at position %d", p.pos)
+	}
+	p.skipSpaces()
+
+	if p.eof() {
+		return attr, fmt.Errorf("unterminated attribute selector")
+	}
+
+	if p.input[p.pos] == ']' {
+		p.pos++
+		return attr, nil
+	}
+
+	switch op := p.input[p.pos]; op {
+	case '=':
+		attr.operator = "="
+		p.pos++
+	case '~', '|', '^', '$', '*':
+		if p.pos+1 >= len(p.input) || p.input[p.pos+1] != '=' {
+			return attr, fmt.Errorf("invalid attribute operator at position %d", p.pos)
+		}
+		attr.operator = string(op) + "="
+		p.pos += 2
+	default:
+		return attr, fmt.Errorf("invalid attribute operator at position %d", p.pos)
+	}
+
+	p.skipSpaces()
+	value, err := p.parseValue()
+	if err != nil {
+		return attr, err
+	}
+	attr.value = value
+	p.skipSpaces()
+
+	if !p.eof() && (p.input[p.pos] == 'i' || p.input[p.pos] == 'I') {
+		attr.caseInsensitive = true
+		p.pos++
+		p.skipSpaces()
+	}
+
+	if p.eof() || p.input[p.pos] != ']' {
+		return attr, fmt.Errorf("unterminated attribute selector")
+	}
+	p.pos++
+
+	return attr, nil
+}
+
+func (p *selectorParser) parseValue() (string, error) {
+	if p.eof() {
+		return "", fmt.Errorf("expected attribute value at position %d", p.pos)
+	}
+
+	quote := p.input[p.pos]
+	if quote != '"' && quote != '\'' {
+		value := p.parseIdent()
+		if value == "" {
+			return "", fmt.Errorf("expected attribute value at position %d", p.pos)
+		}
+		return value, nil
+	}
+
+	end := strings.IndexByte(p.input[p.pos+1:], quote)
+	if end < 0 {
+		return "", fmt.Errorf("unterminated string at position %d", p.pos)
+	}
+
+	value := p.input[p.pos+1 : p.pos+1+end]
+	p.pos += end + 2
+
+	return value, nil
+}
+
+func (p *selectorParser) parseIdent() string {
+	start := p.pos
+	for !p.eof() && isIdentChar(p.input[p.pos]) {
+		p.pos++
+	}
+	return p.input[start:p.pos]
+}
+
+func (p *selectorParser) skipSpaces() bool {
+	start := p.pos
+	for !p.eof() && isSpace(p.input[p.pos]) {
+		p.pos++
+	}
+	return p.pos > start
+}
+
+func (p *selectorParser) eof() bool {
+	return p.pos >= len(p.input)
+}
+
+func isIdentChar(c byte) bool {
+	return c == '-' || c == '_' || c >= 0x80 ||
+		(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
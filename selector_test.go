@@ -0,0 +1,102 @@
+package sanitize_test
+
+import (
+	"testing"
+
+	"github.com/sonalys/sanitize"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/html/atom"
+)
+
+func Test_ParseSelector(t *testing.T) {
+	valid := []string{
+		"*",
+		"td",
+		"table.newsletter td[bgcolor]",
+		"div > p.a.b#c",
+		`a[href^="http"], img[src$='.png' i]`,
+		"[data-x~=y]",
+	}
+
+	for _, selector := range valid {
+		_, err := sanitize.ParseSelector(selector)
+		require.NoError(t, err, selector)
+	}
+
+	invalid := []string{
+		"",
+		"div >",
+		"a,",
+		"a:hover",
+		"[href",
+		`[href="a]`,
+		"[href!=a]",
+		"div.",
+	}
+
+	for _, selector := range invalid {
+		_, err := sanitize.ParseSelector(selector)
+		require.Error(t, err, selector)
+	}
+}
+
+func Test_Select(t *testing.T) {
+	t.Run("descendant combinator", func(t *testing.T) {
+		got := sanitizeWith(t,
+			`<table class="x newsletter"><tr><td bgcolor="red"></td></tr></table><table><tr><td bgcolor="red"></td></tr></table>`,
+			sanitize.Select("table.newsletter td[bgcolor]", sanitize.BlockAttrs("bgcolor")),
+		)
+		require.Equal(t, `<html><head></head><body><table class="x newsletter"><tbody><tr><td></td></tr></tbody></table><table><tbody><tr><td bgcolor="red"></td></tr></tbody></table></body></html>`, got)
+	})
+
+	t.Run("child combinator", func(t *testing.T) {
+		got := sanitizeWith(t,
+			`<div id="a"><p>1</p><span><p>2</p></span></div>`,
+			sanitize.Select("#a > p", sanitize.TagPolicy((*sanitize.Tag).Block)),
+		)
+		require.Equal(t, `<html><head></head><body><div id="a"><span><p>2</p></span></div></body></html>`, got)
+	})
+
+	t.Run("attribute operators", func(t *testing.T) {
+		got := sanitizeWith(t,
+			`<a href="http://a">1</a><a href="https://a">2</a><a lang="en-US">3</a><a rel="a  nofollow">4</a><a href="/x.PNG">5</a>`,
+			sanitize.Select(`a[href^="http:"], [lang|=en], [rel~=nofollow], [href$=".png" i]`, sanitize.TagPolicy((*sanitize.Tag).Block)),
+		)
+		require.Equal(t, `<html><head></head><body><a href="https://a">2</a></body></html>`, got)
+	})
+
+	t.Run("should match blocked and changed attributes", func(t *testing.T) {
+		got := sanitizeWith(t,
+			`<div class="a" id="b"><p>1</p></div><div><p>2</p></div>`,
+			sanitize.BlockAttrs("class"),
+			sanitize.NamespaceIDs("user-"),
+			sanitize.Select(".a > p, #b p", sanitize.TagPolicy((*sanitize.Tag).Block)),
+		)
+		require.Equal(t, `<html><head></head><body><div id="user-b"></div><div><p>2</p></div></body></html>`, got)
+	})
+
+	t.Run("should match original attributes under email policies", func(t *testing.T) {
+		got := sanitizeWith(t,
+			`<table class="newsletter"><tr><td>1</td></tr></table><table><tr><td>2</td></tr></table>`,
+			sanitize.DefaultEmailPolicies(),
+			sanitize.Select("table.newsletter td", sanitize.TagPolicy((*sanitize.Tag).Block)),
+		)
+		require.Equal(t, `<html><head></head><body><table><tbody><tr></tr></tbody></table><table><tbody><tr><td>2</td></tr></tbody></table></body></html>`, got)
+	})
+
+	t.Run("should match renamed tags", func(t *testing.T) {
+		got := sanitizeWith(t,
+			`<b class="a">1</b><strong class="b">2</strong>`,
+			sanitize.RenameTags(map[atom.Atom]atom.Atom{atom.B: atom.Strong}),
+			sanitize.BlockAttrs("class"),
+			sanitize.Select("strong.a", sanitize.TagPolicy((*sanitize.Tag).Block)),
+		)
+		require.Equal(t, `<html><head></head><body><strong>2</strong></body></html>`, got)
+	})
+
+	t.Run("should panic on invalid selector", func(t *testing.T) {
+		require.Panics(t, func() {
+			sanitize.Select("a:hover")
+		})
+	})
+}
@@ -1,6 +1,12 @@
 package sanitize
 
-import "golang.org/x/net/html/atom"
+import (
+	"slices"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
 
 // Tag represents an HTML tag.
 //
@@ -12,6 +18,19 @@ type Tag struct {
 	attributes []*Attribute
 	data       string
 	blocked    bool
+	denied     bool
+	unwrapped  bool
+
+	parent   *Tag
+	node     *html.Node
+	original []html.Attribute
+	doc      *document
+	finals   []Policy
+
+	text        *string
+	prepend     []string
+	append      []string
+	replacement *string
 }
 
 // Block will remove the tag from the sanitized output.
@@ -28,12 +47,40 @@ func (t *Tag) Block() {
 // Inner content will still be sanitized.
 //
 // Allowing a previously blocked tag will return it to the output.
+// Denied tags cannot be allowed.
 //
 // Tags are allowed by default.
 func (t *Tag) Allow() {
 	t.blocked = false
 }
 
+// Deny will remove the tag from the sanitized output.
+// All inner content will also be removed.
+//
+// Unlike Block, this decision is final, subsequent policies cannot allow the tag again.
+func (t *Tag) Deny() {
+	t.denied = true
+}
+
+// Unwrap will remove the tag from the sanitized output, keeping its content.
+// Inner content is sanitized as part of the tag's parent.
+//
+// Unwrapping happens even for blocked tags, but not for denied ones.
+// Content mutations, like SetText or AppendHTML, are ignored for unwrapped tags.
+func (t *Tag) Unwrap() {
+	t.unwrapped = true
+}
+
+// IsBlocked checks if the tag will be removed from the sanitized output.
+func (t *Tag) IsBlocked() bool {
+	return t.blocked || t.denied
+}
+
+// IsDenied checks if the tag was denied, and cannot be allowed anymore.
+func (t *Tag) IsDenied() bool {
+	return t.denied
+}
+
 // AttrPolicy will enforce any attribute scoped policy into the parent tag.
 // Attributes can be added, removed or updated.
 // All attributes are allowed by default.
@@ -57,13 +104,16 @@ func (t *Tag) HasAttr(key string) bool {
 }
 
 // UpsertAttr will update a tag's attribute, if it already exists, or create a new one.
+// Updating a denied attribute will keep it denied.
 func (t *Tag) UpsertAttr(namespace, key, value string) {
 	attr := NewAttribute(namespace, key, value)
 
 	for i := range t.attributes {
-		if cur := t.attributes[i]; cur.Namespace() != attr.Namespace() || cur.Key() != attr.Key() {
+		cur := t.attributes[i]
+		if cur.Namespace() != attr.Namespace() || cur.Key() != attr.Key() {
 			continue
 		}
+		attr.denied = cur.denied
 		t.attributes[i] = attr
 		return
 	}
@@ -71,6 +121,65 @@ func (t *Tag) UpsertAttr(namespace, key, value string) {
 	t.attributes = append(t.attributes, attr)
 }
 
+// attrValue returns the raw value of the first allowed attribute with the given normalized key.
+func (t *Tag) attrValue(key string) (string, bool) {
+	for _, attr := range t.attributes {
+		if attr.Key() == key && !attr.IsBlocked() {
+			return attr.value, true
+		}
+	}
+
+	return "", false
+}
+
+// originalAttrValue returns the raw value of the first attribute with the given normalized key, as found
+// in the document before policies blocked or changed it. Tags outside a document use their allowed attributes.
+func (t *Tag) originalAttrValue(key string) (string, bool) {
+	if t.node == nil {
+		return t.attrValue(key)
+	}
+
+	for _, attr := range t.original {
+		if Normalize(attr.Key) == key {
+			return attr.Val, true
+		}
+	}
+
+	return "", false
+}
+
+// Text returns the text content of the tag, as seen before its content is sanitized.
+// Script, style and template contents are not considered text.
+func (t *Tag) Text() string {
+	if t.node == nil {
+		return ""
+	}
+
+	var b strings.Builder
+	var walk func(node *html.Node)
+	walk = func(node *html.Node) {
+		for child := range node.ChildNodes() {
+			switch {
+			case child.Type == html.TextNode:
+				b.WriteString(child.Data)
+			case child.Type != html.ElementNode:
+			case child.DataAtom == atom.Script, child.DataAtom == atom.Style, child.DataAtom == atom.Template:
+			default:
+				walk(child)
+			}
+		}
+	}
+	walk(t.node)
+
+	return b.String()
+}
+
+// Parent returns the closest ancestor tag, or nil for the root tag.
+// The parent's policies are always applied before its children's.
+func (t *Tag) Parent() *Tag {
+	return t.parent
+}
+
 func (t *Tag) Atom() atom.Atom {
 	return t.atom
 }
@@ -83,6 +192,120 @@ func (t *Tag) Data() string {
 	return t.data
 }
 
+// SetData will rename the tag.
+// The tag's atom is updated accordingly, and is zero for non-standard tags.
 func (t *Tag) SetData(value string) {
 	t.data = value
+	t.atom = atom.Lookup([]byte(strings.ToLower(value)))
+}
+
+// SetAtom will rename the tag to the given atom.
+func (t *Tag) SetAtom(value atom.Atom) {
+	t.atom = value
+	t.data = value.String()
+}
+
+// SetStyle will set a CSS property in the tag's style attribute, replacing any previous value.
+// Unsafe values, like the ones containing urls, comments or declaration separators, are ignored.
+//
+// The style attribute is allowed, whether it is new or already exists, as the declaration was validated.
+// Declarations of a blocked style attribute are removed instead of being allowed with it,
+// and denied style attributes are not changed. Style sanitizers, like AllowStyles, can be declared
+// after the policy calling SetStyle to restrict its declarations.
+func (t *Tag) SetStyle(property, value string) {
+	property = strings.ToLower(strings.TrimSpace(property))
+	value = strings.TrimSpace(value)
+	if !isSafeStyleValue(property) || !isSafeStyleValue(value) || strings.Contains(property, ":") {
+		return
+	}
+
+	for _, attr := range t.attributes {
+		if attr.Key() != "style" {
+			continue
+		}
+
+		if attr.IsDenied() {
+			return
+		}
+
+		var decls []styleDeclaration
+		if !attr.IsBlocked() {
+			decls, _ = parseStyle(attr.value)
+		}
+
+		i := 0
+		for ; i < len(decls); i++ {
+			if decls[i].property == property {
+				decls[i].value = value
+				break
+			}
+		}
+		if i == len(decls) {
+			decls = append(decls, styleDeclaration{property: property, value: value})
+		}
+
+		attr.SetValue(renderStyle(decls))
+		attr.Allow()
+		return
+	}
+
+	t.attributes = append(t.attributes, NewAttribute("", "style", renderStyle([]styleDeclaration{
+		{property: property, value: value},
+	})))
+}
+
+// MergeAttrTokens will add the tokens to a space separated attribute, like rel or class.
+// Existing tokens are kept, and duplicated tokens are ignored.
+//
+// Blocked attributes are replaced by an allowed attribute with only the given tokens.
+func (t *Tag) MergeAttrTokens(key string, tokens ...string) {
+	value, _ := t.attrValue(Normalize(key))
+	merged := strings.Fields(value)
+
+	for _, token := range tokens {
+		if !slices.ContainsFunc(merged, func(cur string) bool { return strings.EqualFold(cur, token) }) {
+			merged = append(merged, token)
+		}
+	}
+
+	t.UpsertAttr("", key, strings.Join(merged, " "))
+}
+
+// RemoveAttr will permanently remove all attributes with the given key.
+// Unlike blocking, removed attributes cannot be allowed by subsequent policies.
+//
+// Denied attributes are kept, as they are never rendered, so attributes upserted later
+// with the same key are still denied.
+func (t *Tag) RemoveAttr(key string) {
+	normalizedKey := Normalize(key)
+	t.attributes = slices.DeleteFunc(t.attributes, func(attr *Attribute) bool {
+		return attr.Key() == normalizedKey && !attr.IsDenied()
+	})
+}
+
+// SetText will replace all the tag's content with the given text.
+// The text is escaped when rendering the sanitized output.
+func (t *Tag) SetText(text string) {
+	t.text = &text
+}
+
+// PrependHTML will insert the HTML content at the beginning of the tag's content.
+// The content is sanitized by the same policies, but cannot insert more content.
+func (t *Tag) PrependHTML(content string) {
+	t.prepend = append(t.prepend, content)
+}
+
+// AppendHTML will insert the HTML content at the end of the tag's content.
+// The content is sanitized by the same policies, but cannot insert more content.
+func (t *Tag) AppendHTML(content string) {
+	t.append = append(t.append, content)
+}
+
+// ReplaceWith will replace the tag and all its content with the given HTML content.
+// The replacement happens even for blocked or denied tags, allowing policies to
+// replace removed content with a notice.
+//
+// The content is sanitized by the same policies, but cannot insert more content.
+func (t *Tag) ReplaceWith(content string) {
+	t.replacement = &content
 }
@@ -5,6 +5,7 @@ import (
 
 	"github.com/sonalys/sanitize"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/net/html/atom"
 )
 
 func Test_Tag_UpsertAttr(t *testing.T) {
@@ -53,3 +54,121 @@ func Test_Tag_HasAttr(t *testing.T) {
 		require.True(t, got)
 	})
 }
+
+func Test_Tag_Deny(t *testing.T) {
+	t.Run("should not be allowed", func(t *testing.T) {
+		tag := sanitize.Tag{}
+
+		tag.Deny()
+		tag.Allow()
+
+		require.True(t, tag.IsBlocked())
+		require.True(t, tag.IsDenied())
+	})
+
+	t.Run("blocked should be allowed", func(t *testing.T) {
+		tag := sanitize.Tag{}
+
+		tag.Block()
+		tag.Allow()
+
+		require.False(t, tag.IsBlocked())
+		require.False(t, tag.IsDenied())
+	})
+}
+
+func Test_Tag_SetData(t *testing.T) {
+	tag := sanitize.Tag{}
+
+	tag.SetData("STRONG")
+	require.Equal(t, atom.Strong, tag.Atom())
+
+	tag.SetData("custom")
+	require.Equal(t, atom.Atom(0), tag.Atom())
+}
+
+func Test_Tag_SetStyle(t *testing.T) {
+	t.Run("should replace existing properties", func(t *testing.T) {
+		tag := sanitize.Tag{}
+		tag.UpsertAttr("", "style", "color: blue; margin: 0")
+
+		tag.SetStyle("Color", "red")
+
+		attr := tag.Attrs()[0]
+		require.Equal(t, "color:red;margin:0", attr.UnsafeValue())
+	})
+
+	t.Run("should allow new attributes", func(t *testing.T) {
+		tag := sanitize.Tag{}
+
+		tag.SetStyle("margin", "0")
+
+		attr := tag.Attrs()[0]
+		require.Equal(t, "margin:0", attr.UnsafeValue())
+		require.False(t, attr.IsBlocked())
+	})
+
+	t.Run("should allow blocked attributes without their declarations", func(t *testing.T) {
+		tag := sanitize.Tag{}
+		tag.UpsertAttr("", "style", "position: fixed")
+		tag.Attrs()[0].Block()
+
+		tag.SetStyle("margin", "0")
+
+		attr := tag.Attrs()[0]
+		require.Equal(t, "margin:0", attr.UnsafeValue())
+		require.False(t, attr.IsBlocked())
+	})
+
+	t.Run("should not change denied attributes", func(t *testing.T) {
+		tag := sanitize.Tag{}
+		tag.UpsertAttr("", "style", "color: blue")
+		tag.Attrs()[0].Deny()
+
+		tag.SetStyle("margin", "0")
+
+		attr := tag.Attrs()[0]
+		require.Equal(t, "color: blue", attr.UnsafeValue())
+		require.True(t, attr.IsDenied())
+	})
+
+	t.Run("should ignore unsafe values", func(t *testing.T) {
+		tag := sanitize.Tag{}
+
+		tag.SetStyle("background", "url(http://tracker)")
+		tag.SetStyle("color", "red;position:fixed")
+		tag.SetStyle("color", `"red`)
+		tag.SetStyle("background-image", `image-set("https://tracker/a.png" 1x)`)
+		tag.SetStyle("background-image", `-WEBKIT-image-set("https://tracker/a.png" 1x)`)
+
+		require.Empty(t, tag.Attrs())
+	})
+}
+
+func Test_Tag_RemoveAttr(t *testing.T) {
+	t.Run("should remove attributes", func(t *testing.T) {
+		tag := sanitize.Tag{}
+		tag.UpsertAttr("", "key", "value")
+		tag.UpsertAttr("", "other", "value")
+
+		tag.RemoveAttr("KEY")
+
+		attrs := tag.Attrs()
+		require.Len(t, attrs, 1)
+		require.Equal(t, "other", attrs[0].Key())
+	})
+
+	t.Run("should keep denied attributes", func(t *testing.T) {
+		tag := sanitize.Tag{}
+		tag.UpsertAttr("", "key", "value")
+		tag.Attrs()[0].Deny()
+
+		tag.RemoveAttr("key")
+		tag.UpsertAttr("", "key", "other")
+
+		attrs := tag.Attrs()
+		require.Len(t, attrs, 1)
+		require.Equal(t, "other", attrs[0].UnsafeValue())
+		require.True(t, attrs[0].IsDenied())
+	})
+}
@@ -0,0 +1,82 @@
+package sanitize
+
+type (
+	// Text represents an HTML text or comment node.
+	//
+	// Any modifications to this structure will impact on the sanitization result.
+	//
+	// Texts are allowed by default, and escaped when rendering the sanitized output.
+	// Texts inside raw text tags, like style or script, are not escaped.
+	Text struct {
+		data    string
+		parent  *Tag
+		blocked bool
+		comment bool
+
+		finals []textApplier
+	}
+
+	// TextPolicy is a text supervisor. It allows, blocks or modifies text and comment nodes.
+	// Any modifications will be propagated to the content rendering.
+	//
+	// Text policies are ignored by tags, and can be combined with other policies.
+	TextPolicy func(text *Text)
+
+	// textApplier is implemented by policies handling text nodes.
+	textApplier interface {
+		ApplyText(text *Text)
+	}
+)
+
+// Apply does nothing, text policies only handle text nodes.
+func (p TextPolicy) Apply(*Tag) {}
+
+func (p TextPolicy) ApplyText(text *Text) {
+	p(text)
+}
+
+func (p Policies) ApplyText(text *Text) {
+	for _, policy := range p {
+		if applier, ok := policy.(textApplier); ok {
+			applier.ApplyText(text)
+		}
+	}
+}
+
+func (p finalPolicy) ApplyText(text *Text) {
+	if applier, ok := p.Policy.(textApplier); ok {
+		text.finals = append(text.finals, applier)
+	}
+}
+
+// Block will remove the text from the sanitized output.
+func (t *Text) Block() {
+	t.blocked = true
+}
+
+// Allow will allow the text in the sanitized output.
+func (t *Text) Allow() {
+	t.blocked = false
+}
+
+func (t *Text) IsBlocked() bool {
+	return t.blocked
+}
+
+func (t *Text) Data() string {
+	return t.data
+}
+
+func (t *Text) SetData(value string) {
+	t.data = value
+}
+
+// IsComment checks if the text is an HTML comment, like <!-- comment -->.
+func (t *Text) IsComment() bool {
+	return t.comment
+}
+
+// Parent returns the tag containing the text, or nil for texts outside tags.
+func (t *Text) Parent() *Tag {
+	return t.parent
+}
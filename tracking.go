@@ -0,0 +1,117 @@
+package sanitize
+
+import (
+	"net/url"
+	"path"
+	"strings"
+)
+
+// trackingParams are query parameters known to be used for tracking the recipient or campaign.
+// Parameters are matched ignoring case, and support * wildcards.
+var trackingParams = []string{
+	"utm_*",
+	"_ga",
+	"_gl",
+	"_hsenc",
+	"_hsmi",
+	"_openstat",
+	"__hssc",
+	"__hstc",
+	"__hsfp",
+	"dclid",
+	"fbclid",
+	"gbraid",
+	"gclid",
+	"gclsrc",
+	"hsctatracking",
+	"igshid",
+	"li_fat_id",
+	"mc_cid",
+	"mc_eid",
+	"mkt_tok",
+	"ml_subscriber",
+	"ml_subscriber_hash",
+	"msclkid",
+	"oly_anon_id",
+	"oly_enc_id",
+	"rb_clickid",
+	"s_cid",
+	"sc_cid",
+	"ttclid",
+	"twclid",
+	"vero_conv",
+	"vero_id",
+	"wbraid",
+	"wickedid",
+	"yclid",
+}
+
+// StripTrackingParams removes known tracking query parameters from href attributes,
+// like utm_source, fbclid, gclid or mc_eid.
+//
+// It accepts extra as additional parameters to be removed, supporting * wildcards, like "ref_*".
+// The remaining URL is kept as is, including the order of the other parameters.
+func StripTrackingParams(extra ...string) Policy {
+	patterns := make([]string, 0, len(trackingParams)+len(extra))
+	patterns = append(patterns, trackingParams...)
+
+	for _, pattern := range extra {
+		patterns = append(patterns, strings.ToLower(strings.TrimSpace(pattern)))
+	}
+
+	return WhenAttr(OnAttrs("href"), func(attr *Attribute) {
+		if value, changed := stripQueryParams(attr.value, patterns); changed {
+			attr.SetValue(value)
+		}
+	})
+}
+
+// stripQueryParams removes the query parameters matching any of the patterns from the raw URL.
+func stripQueryParams(rawURL string, patterns []string) (string, bool) {
+	start := strings.IndexByte(rawURL, '?')
+	if start < 0 {
+		return rawURL, false
+	}
+
+	end := len(rawURL)
+	if i := strings.IndexByte(rawURL, '#'); i >= 0 {
+		if i < start {
+			return rawURL, false
+		}
+		end = i
+	}
+
+	params := strings.Split(rawURL[start+1:end], "&")
+	kept := params[:0]
+
+	for _, param := range params {
+		key, _, _ := strings.Cut(param, "=")
+		if unescaped, err := url.QueryUnescape(key); err == nil {
+			key = unescaped
+		}
+
+		if !matchParam(patterns, strings.ToLower(key)) {
+			kept = append(kept, param)
+		}
+	}
+
+	if len(kept) == len(params) {
+		return rawURL, false
+	}
+
+	query := strings.Join(kept, "&")
+	if query != "" {
+		query = "?" + query
+	}
+
+	return rawURL[:start] + query + rawURL[end:], true
+}
+
+func matchParam(patterns []string, key string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, key); matched {
+			return true
+		}
+	}
+	return false
+}
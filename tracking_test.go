@@ -0,0 +1,28 @@
+package sanitize_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/sonalys/sanitize"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_StripTrackingParams(t *testing.T) {
+	content := []byte(`<a href="https://shop.example/p?id=1&amp;utm_source=mail&amp;UTM_Campaign=x&amp;fbclid=abc&amp;color=red#utm_source=keep">1</a>` +
+		`<a href="https://shop.example/p?mc_eid=1&amp;ref_user=2">2</a>` +
+		`<a href="https://shop.example/p?id=1">3</a>` +
+		`<img src="https://shop.example/p?utm_source=mail"/>`)
+	out := bytes.NewBuffer(make([]byte, 0, 1024))
+	err := sanitize.HTML(bytes.NewReader(content), out,
+		sanitize.StripTrackingParams("REF_*"),
+	)
+	require.NoError(t, err)
+
+	require.Equal(t, `<html><head></head><body>`+
+		`<a href="https://shop.example/p?id=1&amp;color=red#utm_source=keep">1</a>`+
+		`<a href="https://shop.example/p">2</a>`+
+		`<a href="https://shop.example/p?id=1">3</a>`+
+		`<img src="https://shop.example/p?utm_source=mail"/>`+
+		`</body></html>`, out.String())
+}
@@ -0,0 +1,82 @@
+package sanitize
+
+import (
+	"strings"
+)
+
+// UnicodeClass is a set of invisible or dangerous unicode character classes.
+type UnicodeClass uint
+
+const (
+	// BidiControls are the bidirectional overrides, embeddings, isolates and marks,
+	// like the right-to-left override U+202E, used for spoofing file names as "exe.pdf".
+	BidiControls UnicodeClass = 1 << iota
+	// ZeroWidth are the zero-width spaces, joiners and no-break spaces, commonly used for fingerprinting.
+	// Removing zero-width joiners also splits emoji sequences.
+	ZeroWidth
+	// InvisibleFormatting are other invisible formatting characters, like soft hyphens,
+	// invisible math operators, Hangul fillers and tag characters.
+	InvisibleFormatting
+
+	// DangerousUnicode contains all unicode classes.
+	DangerousUnicode = BidiControls | ZeroWidth | InvisibleFormatting
+)
+
+// StripUnicode removes the characters of the given classes from all texts.
+//
+// Example:
+//
+//	sanitize.StripUnicode(sanitize.BidiControls | sanitize.ZeroWidth)
+func StripUnicode(classes UnicodeClass) Policy {
+	return TextPolicy(func(text *Text) {
+		text.SetData(strings.Map(func(r rune) rune {
+			if classes&unicodeClassOf(r) != 0 {
+				return -1
+			}
+			return r
+		}, text.data))
+	})
+}
+
+// EscapeUnicode replaces the characters of the given classes in all texts with their
+// visible escaped equivalent, like \u202e, revealing them to the user.
+func EscapeUnicode(classes UnicodeClass) Policy {
+	return TextPolicy(func(text *Text) {
+		if strings.IndexFunc(text.data, func(r rune) bool { return classes&unicodeClassOf(r) != 0 }) < 0 {
+			return
+		}
+
+		var b strings.Builder
+		for _, r := range text.data {
+			if classes&unicodeClassOf(r) != 0 {
+				b.WriteString(ASCII(string(r)))
+				continue
+			}
+			b.WriteRune(r)
+		}
+
+		text.SetData(b.String())
+	})
+}
+
+// unicodeClassOf returns the class of the character, or zero for regular characters.
+func unicodeClassOf(r rune) UnicodeClass {
+	switch {
+	case r == 0x061C, r == 0x200E, r == 0x200F,
+		r >= 0x202A && r <= 0x202E,
+		r >= 0x2066 && r <= 0x2069:
+		return BidiControls
+	case r >= 0x200B && r <= 0x200D,
+		r == 0x2060, r == 0xFEFF, r == 0x180E:
+		return ZeroWidth
+	case r == 0x00AD, r == 0x034F, r == 0x115F, r == 0x1160,
+		r == 0x17B4, r == 0x17B5, r == 0x3164, r == 0xFFA0,
+		r >= 0x2061 && r <= 0x2064,
+		r >= 0x206A && r <= 0x206F,
+		r >= 0xFFF9 && r <= 0xFFFB,
+		r >= 0xE0000 && r <= 0xE007F:
+		return InvisibleFormatting
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,61 @@
+package sanitize_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/sonalys/sanitize"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_StripUnicode(t *testing.T) {
+	content := []byte("<p title=\"a\u202eb\">invoice\u202efdp.exe</p><p>fin\u200bger\u00adprint</p>")
+
+	t.Run("per class", func(t *testing.T) {
+		out := bytes.NewBuffer(make([]byte, 0, 1024))
+		err := sanitize.HTML(bytes.NewReader(content), out,
+			sanitize.StripUnicode(sanitize.BidiControls),
+		)
+		require.NoError(t, err)
+
+		require.Equal(t, "<html><head></head><body><p title=\"a\u202eb\">invoicefdp.exe</p><p>fin\u200bger\u00adprint</p></body></html>", out.String())
+	})
+
+	t.Run("all classes", func(t *testing.T) {
+		out := bytes.NewBuffer(make([]byte, 0, 1024))
+		err := sanitize.HTML(bytes.NewReader(content), out,
+			sanitize.StripUnicode(sanitize.DangerousUnicode),
+		)
+		require.NoError(t, err)
+
+		require.Equal(t, "<html><head></head><body><p title=\"a\u202eb\">invoicefdp.exe</p><p>fingerprint</p></body></html>", out.String())
+	})
+}
+
+func Test_EscapeUnicode(t *testing.T) {
+	content := []byte("<p>invoice\u202efdp.exe</p><p>fin\u200bger</p>")
+	out := bytes.NewBuffer(make([]byte, 0, 1024))
+	err := sanitize.HTML(bytes.NewReader(content), out,
+		sanitize.EscapeUnicode(sanitize.BidiControls),
+		sanitize.StripUnicode(sanitize.ZeroWidth),
+	)
+	require.NoError(t, err)
+
+	require.Equal(t, `<html><head></head><body><p>invoice\u202efdp.exe</p><p>finger</p></body></html>`, out.String())
+}
+
+func Test_TextPolicy(t *testing.T) {
+	content := []byte(`<p>keep</p><pre>remove</pre>`)
+	out := bytes.NewBuffer(make([]byte, 0, 1024))
+	err := sanitize.HTML(bytes.NewReader(content), out,
+		sanitize.Final(sanitize.TextPolicy(func(text *sanitize.Text) {
+			if text.Parent().Data() == "pre" {
+				text.Block()
+			}
+		})),
+		sanitize.TextPolicy((*sanitize.Text).Allow),
+	)
+	require.NoError(t, err)
+
+	require.Equal(t, `<html><head></head><body><p>keep</p><pre></pre></body></html>`, out.String())
+}
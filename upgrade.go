@@ -0,0 +1,81 @@
+package sanitize
+
+import (
+	"strings"
+)
+
+type (
+	// UpgradeOption configures UpgradeInsecureURLs.
+	UpgradeOption func(*upgradeConfig)
+
+	upgradeConfig struct {
+		links      bool
+		isInsecure func(host string) bool
+	}
+)
+
+// UpgradeLinks will also upgrade links, like href and action attributes.
+// By default, only resources loaded automatically by the browser are upgraded.
+func UpgradeLinks() UpgradeOption {
+	return func(c *upgradeConfig) {
+		c.links = true
+	}
+}
+
+// BlockInsecureHosts receives a hook for hosts known not to support TLS.
+// URLs from these hosts are denied instead of upgraded.
+func BlockInsecureHosts(isInsecure func(host string) bool) UpgradeOption {
+	return func(c *upgradeConfig) {
+		c.isInsecure = isInsecure
+	}
+}
+
+// UpgradeInsecureURLs will rewrite http URLs into https, preventing mixed-content warnings.
+// By default, it applies to src, srcset, background, poster, url() in style attributes and stylesheet links.
+//
+// Example:
+//
+//	sanitize.UpgradeInsecureURLs(sanitize.UpgradeLinks())
+func UpgradeInsecureURLs(opts ...UpgradeOption) Policy {
+	var config upgradeConfig
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	rewriter := func(rawURL string) (string, bool) {
+		u, err := parseURL(rawURL)
+		if err != nil {
+			return "", false
+		}
+
+		if !strings.EqualFold(u.Scheme, "http") {
+			return rawURL, true
+		}
+
+		// Hosts are read the way browsers do, like old.example from http:old.example.
+		u, ok := urlHost(u)
+		if !ok {
+			return "", false
+		}
+
+		if config.isInsecure != nil && config.isInsecure(normalizeHost(u.Hostname())) {
+			return "", false
+		}
+
+		u.Scheme = "https"
+		if u.Port() == "80" {
+			u.Host = u.Hostname()
+			if strings.Contains(u.Host, ":") {
+				u.Host = "[" + u.Host + "]"
+			}
+		}
+
+		return u.String(), true
+	}
+
+	if config.links {
+		return RewriteURLs(rewriter)
+	}
+
+	return RewriteResourceURLs(rewriter)
+}
@@ -0,0 +1,33 @@
+package sanitize_test
+
+import (
+	"testing"
+
+	"github.com/sonalys/sanitize"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_UpgradeInsecureURLs(t *testing.T) {
+	content := `<a href="http://a.example/">a</a><img src="HTTP://a.example:80/a.png" srcset="http://b.example/b.png 2x" style="background:url(http://c.example/c.png)"/><img src="http://old.example/a.png"/><img src="https://secure.example/a.png"/>` +
+		`<link rel="Alternate StyleSheet" href="http://d.example/d.css"/><link rel="author" href="http://e.example/"/>` +
+		`<img src="http:old.example/b.png"/><img src="http:/f.example/f.png?x=1#y"/>`
+
+	t.Run("resources only", func(t *testing.T) {
+		got := sanitizeWith(t, content, sanitize.UpgradeInsecureURLs())
+		require.Equal(t, `<html><head></head><body><a href="http://a.example/">a</a><img src="https://a.example/a.png" srcset="https://b.example/b.png 2x" style="background:url(&#34;https://c.example/c.png&#34;)"/><img src="https://old.example/a.png"/><img src="https://secure.example/a.png"/>`+
+			`<link rel="Alternate StyleSheet" href="https://d.example/d.css"/><link rel="author" href="http://e.example/"/>`+
+			`<img src="https://old.example/b.png"/><img src="https://f.example/f.png?x=1#y"/></body></html>`, got)
+	})
+
+	t.Run("links and insecure hosts", func(t *testing.T) {
+		got := sanitizeWith(t, content, sanitize.UpgradeInsecureURLs(
+			sanitize.UpgradeLinks(),
+			sanitize.BlockInsecureHosts(func(host string) bool {
+				return host == "old.example"
+			}),
+		))
+		require.Equal(t, `<html><head></head><body><a href="https://a.example/">a</a><img src="https://a.example/a.png" srcset="https://b.example/b.png 2x" style="background:url(&#34;https://c.example/c.png&#34;)"/><img/><img src="https://secure.example/a.png"/>`+
+			`<link rel="Alternate StyleSheet" href="https://d.example/d.css"/><link rel="author" href="https://e.example/"/>`+
+			`<img/><img src="https://f.example/f.png?x=1#y"/></body></html>`, got)
+	})
+}
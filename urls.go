@@ -0,0 +1,304 @@
+package sanitize
+
+import (
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html/atom"
+)
+
+// URLRewriter receives a URL from an attribute, and returns its replacement.
+// Returning false removes the URL from the sanitized output.
+type URLRewriter func(rawURL string) (string, bool)
+
+// urlAttrs are the normalized keys of attributes containing a single URL.
+var urlAttrs = map[string]struct{}{
+	"action":     {},
+	"background": {},
+	"cite":       {},
+	"formaction": {},
+	"href":       {},
+	"longdesc":   {},
+	"lowsrc":     {},
+	"poster":     {},
+	"src":        {},
+	"xlink:href": {},
+}
+
+// resourceAttrs are the normalized keys of attributes loading resources automatically.
+var resourceAttrs = map[string]struct{}{
+	"background": {},
+	"lowsrc":     {},
+	"poster":     {},
+	"src":        {},
+	"srcset":     {},
+	"style":      {},
+}
+
+// RewriteURLs applies the rewriter to all URLs contained in attributes:
+//   - Single URL attributes, like href, src, action or background.
+//   - Each image candidate in srcset attributes.
+//   - Each space separated URL in ping attributes.
+//   - Each url() in style attributes.
+//
+// URLs removed by the rewriter deny single URL attributes, remove the srcset candidate or ping URL,
+// or the style declaration containing it.
+func RewriteURLs(rewriter URLRewriter) Policy {
+	return AttributePolicy(func(attr *Attribute) {
+		rewriteAttrURLs(attr, rewriter)
+	})
+}
+
+// RewriteResourceURLs is like RewriteURLs, but only applies to URLs loaded automatically
+// by the browser, like src, srcset, background, poster, url() in style attributes,
+// and the href of stylesheet links.
+func RewriteResourceURLs(rewriter URLRewriter) Policy {
+	return TagPolicy(func(tag *Tag) {
+		stylesheet := tag.atom == atom.Link && isStylesheetLink(tag)
+
+		for _, attr := range tag.attributes {
+			if isResourceAttr(attr) || stylesheet && attr.Key() == "href" {
+				rewriteAttrURLs(attr, rewriter)
+			}
+		}
+	})
+}
+
+// isStylesheetLink checks if the rel attribute contains the stylesheet token, like rel="alternate stylesheet".
+// Blocked rel attributes are considered, as later policies could allow them.
+func isStylesheetLink(tag *Tag) bool {
+	for _, attr := range tag.attributes {
+		if attr.Key() != "rel" {
+			continue
+		}
+
+		for _, token := range strings.Fields(attr.value) {
+			if strings.EqualFold(token, "stylesheet") {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func isResourceAttr(attr *Attribute) bool {
+	_, ok := resourceAttrs[attr.Key()]
+	return ok
+}
+
+func rewriteAttrURLs(attr *Attribute, rewriter URLRewriter) {
+	switch key := attr.Key(); key {
+	case "srcset":
+		rewriteSrcset(attr, rewriter)
+	case "ping":
+		rewritePing(attr, rewriter)
+	case "style":
+		rewriteStyleURLs(attr, rewriter)
+	default:
+		if _, ok := urlAttrs[key]; !ok {
+			return
+		}
+
+		rawURL := strings.TrimSpace(attr.value)
+		value, ok := rewriter(rawURL)
+		if !ok {
+			attr.Deny()
+			return
+		}
+		if value != rawURL {
+			attr.SetValue(value)
+		}
+	}
+}
+
+func rewriteSrcset(attr *Attribute, rewriter URLRewriter) {
+	var candidates []string
+
+	for _, candidate := range splitSrcset(attr.value) {
+		value, ok := rewriter(candidate[0])
+		if !ok {
+			continue
+		}
+		candidate[0] = value
+
+		candidates = append(candidates, strings.Join(candidate, " "))
+	}
+
+	if len(candidates) == 0 {
+		attr.Deny()
+		return
+	}
+
+	attr.SetValue(strings.Join(candidates, ", "))
+}
+
+func rewritePing(attr *Attribute, rewriter URLRewriter) {
+	var urls []string
+
+	for _, rawURL := range strings.Fields(attr.value) {
+		if value, ok := rewriter(rawURL); ok {
+			urls = append(urls, value)
+		}
+	}
+
+	if len(urls) == 0 {
+		attr.Deny()
+		return
+	}
+
+	attr.SetValue(strings.Join(urls, " "))
+}
+
+// splitSrcset splits a srcset attribute into its candidates, each one being the URL followed by its descriptors.
+// Like browsers, URLs end at the first space, so URLs containing commas, like data URLs, are kept whole.
+func splitSrcset(value string) [][]string {
+	var candidates [][]string
+
+	for {
+		value = strings.TrimLeft(value, ", \t\n\f\r")
+		if value == "" {
+			return candidates
+		}
+
+		end := strings.IndexAny(value, " \t\n\f\r")
+		if end < 0 {
+			end = len(value)
+		}
+
+		rawURL := value[:end]
+		value = value[end:]
+
+		if trimmed := strings.TrimRight(rawURL, ","); trimmed != rawURL {
+			candidates = append(candidates, []string{trimmed})
+			continue
+		}
+
+		end = strings.IndexByte(value, ',')
+		if end < 0 {
+			end = len(value)
+		}
+
+		candidates = append(candidates, append([]string{rawURL}, strings.Fields(value[:end])...))
+		value = value[end:]
+	}
+}
+
+func rewriteStyleURLs(attr *Attribute, rewriter URLRewriter) {
+	decls, changed := parseStyle(attr.value)
+	kept := make([]styleDeclaration, 0, len(decls))
+
+	for _, decl := range decls {
+		value, ok := rewriteCSSURLs(decl.value, rewriter)
+		if !ok {
+			changed = true
+			continue
+		}
+		changed = changed || value != decl.value
+		decl.value = value
+		kept = append(kept, decl)
+	}
+
+	if changed {
+		attr.SetValue(renderStyle(kept))
+	}
+}
+
+// hasOpaqueCSSURLs checks if the CSS value could load URLs that cannot be inspected,
+// like escaped functions, or strings in image-set and -webkit-image-set.
+func hasOpaqueCSSURLs(value string) bool {
+	return strings.Contains(value, "\\") || indexFold(value, "image-set(") >= 0
+}
+
+// rewriteCSSURLs applies the rewriter to all url() functions in a CSS value.
+// It returns false if any URL was removed, or if the value could load URLs
+// that cannot be inspected, like escaped functions or image-set strings.
+func rewriteCSSURLs(value string, rewriter URLRewriter) (string, bool) {
+	if hasOpaqueCSSURLs(value) {
+		return "", false
+	}
+
+	var b strings.Builder
+
+	for {
+		start := indexFold(value, "url(")
+		if start < 0 {
+			b.WriteString(value)
+			return b.String(), true
+		}
+
+		end := strings.IndexByte(value[start:], ')')
+		if end < 0 {
+			return "", false
+		}
+		end += start
+
+		rawURL := strings.TrimSpace(value[start+len("url(") : end])
+		if len(rawURL) >= 2 && (rawURL[0] == '"' || rawURL[0] == '\'') && rawURL[len(rawURL)-1] == rawURL[0] {
+			rawURL = rawURL[1 : len(rawURL)-1]
+		}
+		if strings.ContainsAny(rawURL, "\"'()") {
+			return "", false
+		}
+
+		rewritten, ok := rewriter(rawURL)
+		if !ok || strings.ContainsAny(rewritten, "\"'()") {
+			return "", false
+		}
+
+		b.WriteString(value[:start])
+		b.WriteString(`url("`)
+		b.WriteString(rewritten)
+		b.WriteString(`")`)
+		value = value[end+1:]
+	}
+}
+
+// indexFold returns the index of the first ASCII case-insensitive occurrence of substr.
+func indexFold(s, substr string) int {
+	for i := 0; i+len(substr) <= len(s); i++ {
+		if strings.EqualFold(s[i:i+len(substr)], substr) {
+			return i
+		}
+	}
+	return -1
+}
+
+// parseURL parses a URL the way browsers would read it from an attribute:
+// leading and trailing spaces are ignored, tabs and new lines are removed and
+// backslashes are handled as slashes.
+func parseURL(rawURL string) (*url.URL, error) {
+	rawURL = strings.TrimFunc(rawURL, func(r rune) bool {
+		return r <= ' '
+	})
+	rawURL = strings.NewReplacer("\t", "", "\n", "", "\r", "", "\\", "/").Replace(rawURL)
+
+	return url.Parse(rawURL)
+}
+
+// AllowURLSchemes keeps only URLs with the given schemes, like https or mailto.
+// Relative URLs are kept, while URLs with other schemes, or that cannot be parsed, are removed.
+//
+// Example:
+//
+//	sanitize.AllowURLSchemes("http", "https", "mailto")
+func AllowURLSchemes(schemes ...string) Policy {
+	set := make(map[string]struct{}, len(schemes))
+
+	for _, scheme := range schemes {
+		set[strings.ToLower(strings.TrimSpace(scheme))] = struct{}{}
+	}
+
+	return RewriteURLs(func(rawURL string) (string, bool) {
+		u, err := parseURL(rawURL)
+		if err != nil {
+			return "", false
+		}
+		if u.Scheme == "" {
+			return rawURL, true
+		}
+
+		_, allowed := set[strings.ToLower(u.Scheme)]
+		return rawURL, allowed
+	})
+}
@@ -0,0 +1,47 @@
+package sanitize_test
+
+import (
+	"testing"
+
+	"github.com/sonalys/sanitize"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_AllowURLSchemes(t *testing.T) {
+	policy := sanitize.AllowURLSchemes("https", "MAILTO")
+
+	tests := []struct {
+		name    string
+		value   string
+		allowed bool
+	}{
+		{name: "allowed scheme", value: "https://a.example", allowed: true},
+		{name: "case insensitive", value: "MailTo:a@a.example", allowed: true},
+		{name: "relative", value: "/path?q=1", allowed: true},
+		{name: "fragment", value: "#section", allowed: true},
+		{name: "other scheme", value: "http://a.example", allowed: false},
+		{name: "javascript", value: "javascript:alert(1)", allowed: false},
+		{name: "obfuscated javascript", value: " java\tscript:alert(1)", allowed: false},
+		{name: "invalid", value: "https://a b:c", allowed: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tag := &sanitize.Tag{}
+			tag.UpsertAttr("", "href", tt.value)
+
+			policy.Apply(tag)
+
+			require.Equal(t, !tt.allowed, tag.Attrs()[0].IsBlocked())
+		})
+	}
+
+	t.Run("should filter srcset candidates", func(t *testing.T) {
+		tag := &sanitize.Tag{}
+		tag.UpsertAttr("", "srcset", "https://a.example/a.png 1x, data:image/png;base64,AA 2x")
+
+		policy.Apply(tag)
+
+		require.Equal(t, "https://a.example/a.png 1x", tag.Attrs()[0].Value())
+	})
+}
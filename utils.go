@@ -84,7 +84,7 @@ func fromAttrs(from []html.Attribute) []*Attribute {
 func toAttrs(from []*Attribute) []html.Attribute {
 	to := make([]html.Attribute, 0, len(from))
 	for i := range from {
-		if from[i].blocked {
+		if from[i].IsBlocked() {
 			continue
 		}
 		to = append(to, html.Attribute{
@@ -95,3 +95,13 @@ func toAttrs(from []*Attribute) []html.Attribute {
 	}
 	return to
 }
+
+// isEventHandler checks if a normalized attribute key is an event handler.
+// Namespace prefixes like xlink:onclick are ignored.
+func isEventHandler(key string) bool {
+	if i := strings.LastIndexByte(key, ':'); i >= 0 {
+		key = key[i+1:]
+	}
+
+	return strings.HasPrefix(key, "on")
+}